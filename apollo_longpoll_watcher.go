@@ -69,9 +69,12 @@ func (w *ApolloLongPollWatcher) Start(ctx context.Context, onChange func()) erro
 			string(payload),
 		)
 
-		req, _ := http.NewRequest("GET", url, nil)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 		resp, err := w.client.Do(req)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
 			time.Sleep(3 * time.Second)
 			continue
 		}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -64,13 +65,19 @@ func NewApolloMultiSource(baseURL, appID, cluster string, namespaces []ApolloNam
 		AppID:      appID,
 		Cluster:    cluster,
 		Namespaces: namespaces,
-		client:     &http.Client{Timeout: 5 * time.Second},
 		cache:      NewApolloCache(),
 		name:       "",
 	}
 
+	// 复用 ApolloSourceOption：只有 client 与 name 对多 namespace Source 有意义
+	holder := &ApolloSource{}
 	for _, opt := range opts {
-		opt(nil) // ignore options not applicable
+		opt(holder)
+	}
+	src.client = holder.client
+	src.name = holder.name
+	if src.client == nil {
+		src.client = &http.Client{Timeout: 5 * time.Second}
 	}
 
 	if src.name == "" {
@@ -81,7 +88,7 @@ func NewApolloMultiSource(baseURL, appID, cluster string, namespaces []ApolloNam
 }
 
 // 拉取单个 namespace 内容
-func (a *ApolloMultiSource) fetchNamespace(namespace string) ([]byte, error) {
+func (a *ApolloMultiSource) fetchNamespace(ctx context.Context, namespace string) ([]byte, error) {
 	url := fmt.Sprintf(
 		"%s/configs/%s/%s/%s",
 		a.BaseURL,
@@ -90,7 +97,7 @@ func (a *ApolloMultiSource) fetchNamespace(namespace string) ([]byte, error) {
 		namespace,
 	)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("ApolloMultiSource: new request failed: %w", err)
 	}
@@ -101,7 +108,7 @@ func (a *ApolloMultiSource) fetchNamespace(namespace string) ([]byte, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		return nil, &HTTPStatusError{URL: url, StatusCode: resp.StatusCode}
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -127,6 +134,11 @@ func (a *ApolloMultiSource) fetchNamespace(namespace string) ([]byte, error) {
 	return json.Marshal(m)
 }
 
+// Name 返回该 Source 的逻辑名称，与 Metadata.Source 一致。
+func (a *ApolloMultiSource) Name() string {
+	return a.name
+}
+
 // Load 实现 Source：
 // 加载所有 namespace 的内容，并合并成一个 JSON map：
 //
@@ -135,10 +147,19 @@ func (a *ApolloMultiSource) fetchNamespace(namespace string) ([]byte, error) {
 //	   "<namespace2>": { ... }
 //	}
 func (a *ApolloMultiSource) Load() ([]byte, Metadata, error) {
+	return a.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+// ctx 被取消时直接返回错误，不会使用缓存兜底（取消是调用方的意图，而非 Apollo 故障）。
+func (a *ApolloMultiSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
 	final := make(map[string]any)
 
 	for _, ns := range a.Namespaces {
-		b, err := a.fetchNamespace(ns.Namespace)
+		b, err := a.fetchNamespace(ctx, ns.Namespace)
+		if err != nil && ctx.Err() != nil {
+			return nil, Metadata{}, ctx.Err()
+		}
 		if err != nil {
 			// fallback
 			if cached, ok := a.cache.Get(ns.Namespace); ok {
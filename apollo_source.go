@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -53,6 +54,16 @@ func WithApolloClient(client *http.Client) ApolloSourceOption {
 	}
 }
 
+// WithApolloTimeout 设置默认 http.Client 的超时时间（默认 5 秒）。
+// 通过 WithApolloClient 注入自定义 client 时该选项不生效。
+func WithApolloTimeout(d time.Duration) ApolloSourceOption {
+	return func(a *ApolloSource) {
+		if d > 0 && a.client == nil {
+			a.client = &http.Client{Timeout: d}
+		}
+	}
+}
+
 // WithApolloSourceName 设置 Source 的逻辑名称
 func WithApolloSourceName(name string) ApolloSourceOption {
 	return func(a *ApolloSource) {
@@ -78,21 +89,33 @@ func NewApolloSource(baseURL, appID, cluster, namespace string, opts ...ApolloSo
 		AppID:     strings.TrimSpace(appID),
 		Cluster:   strings.TrimSpace(cluster),
 		Namespace: strings.TrimSpace(namespace),
-		client:    &http.Client{Timeout: 5 * time.Second},
 		name:      "",
 		Format:    "",
 	}
 	for _, opt := range opts {
 		opt(a)
 	}
+	if a.client == nil {
+		a.client = &http.Client{Timeout: 5 * time.Second}
+	}
 	if a.name == "" {
 		a.name = fmt.Sprintf("apollo[%s:%s:%s]", a.AppID, a.Cluster, a.Namespace)
 	}
 	return a
 }
 
+// Name 返回该 Source 的逻辑名称，与 Metadata.Source 一致。
+func (a *ApolloSource) Name() string {
+	return a.name
+}
+
 // Load 实现 Source 接口，通过 Apollo HTTP API 拉取配置
 func (a *ApolloSource) Load() ([]byte, Metadata, error) {
+	return a.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口，ctx 绑定到 HTTP 请求上。
+func (a *ApolloSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
 	if a.BaseURL == "" || a.AppID == "" || a.Cluster == "" || a.Namespace == "" {
 		return nil, Metadata{}, fmt.Errorf("ApolloSource: missing parameters (BaseURL/AppID/Cluster/Namespace)")
 	}
@@ -102,7 +125,7 @@ func (a *ApolloSource) Load() ([]byte, Metadata, error) {
 	// 例如: http://apollo-server:8080/configs/my-app/default/application
 	url := fmt.Sprintf("%s/configs/%s/%s/%s", a.BaseURL, a.AppID, a.Cluster, a.Namespace)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, Metadata{}, fmt.Errorf("ApolloSource: create request failed: %w", err)
 	}
@@ -115,7 +138,7 @@ func (a *ApolloSource) Load() ([]byte, Metadata, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, Metadata{}, fmt.Errorf("ApolloSource: unexpected status code %d", resp.StatusCode)
+		return nil, Metadata{}, fmt.Errorf("ApolloSource: %w", &HTTPStatusError{URL: url, StatusCode: resp.StatusCode})
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -136,6 +159,7 @@ func (a *ApolloSource) Load() ([]byte, Metadata, error) {
 
 	var respObj struct {
 		Configurations map[string]string `json:"configurations"`
+		ReleaseKey     string            `json:"releaseKey"`
 	}
 	if err := json.Unmarshal(body, &respObj); err != nil {
 		return nil, Metadata{}, fmt.Errorf("ApolloSource: parse response JSON failed: %w", err)
@@ -154,8 +178,9 @@ func (a *ApolloSource) Load() ([]byte, Metadata, error) {
 	}
 
 	meta := Metadata{
-		Format: "json",
-		Source: a.name,
+		Format:   "json",
+		Source:   a.name,
+		Revision: respObj.ReleaseKey,
 	}
 	return dataBytes, meta, nil
 }
@@ -36,7 +36,7 @@ func (w *ApolloWatcher) Start(ctx context.Context, onChange func()) error {
 		case <-ctx.Done():
 			return nil
 		case <-ticker.C:
-			data, _, err := w.source.Load()
+			data, _, err := w.source.LoadContext(ctx)
 			if err != nil {
 				// 加载失败可以 log，但不退出 watcher
 				// fmt.Printf("ApolloWatcher: load failed: %v\n", err)
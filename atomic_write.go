@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic 以“临时文件 + fsync + rename”的方式写文件：
+//  1. 在目标文件所在目录创建临时文件（保证 rename 不跨文件系统）
+//  2. 写入内容并 fsync，确保数据落盘
+//  3. rename 覆盖目标文件，读者要么看到旧内容，要么看到新内容
+//  4. 尽力 fsync 目录，确保 rename 本身持久化
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file in %q failed: %w", dir, err)
+	}
+	tmpName := tmp.Name()
+	// 任何一步失败都清理临时文件；rename 成功后 Remove 会返回 not exist，忽略即可
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file %q failed: %w", tmpName, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync temp file %q failed: %w", tmpName, err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("chmod temp file %q failed: %w", tmpName, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file %q failed: %w", tmpName, err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("rename %q to %q failed: %w", tmpName, path, err)
+	}
+
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
@@ -1,18 +1,25 @@
 package config
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/lifei6671/go-config/decoder"
 )
 
 type Metadata struct {
-	Format string // "json" | "yaml" | "toml" | "env"
-	Source string // 文件路径、URL、标识符等
+	Format   string // "json" | "yaml" | "toml" | "env"
+	Source   string // 文件路径、URL、标识符等
+	Revision string // 后端提供的版本标识（etcd ModRevision、Apollo releaseKey、HTTP ETag 等），可为空
 }
 
 // Config 是整个配置系统的门面接口。
@@ -43,21 +50,91 @@ type Config interface {
 	Keys() []string
 }
 
+// ContextLoader 是支持 context 的加载能力，独立于 Config 以免破坏外部已有的 Config 实现。
+// 调用方可以通过类型断言判断某个 Config 是否支持：
+//
+//	if cl, ok := cfg.(ContextLoader); ok {
+//		err = cl.LoadContext(ctx, sources...)
+//	}
+type ContextLoader interface {
+	// LoadContext 与 Load 相同，ctx 的取消与截止时间会传递给每个 Source。
+	LoadContext(ctx context.Context, sources ...Source) error
+}
+
 // DefaultConfig 是 Config 的默认实现
 type DefaultConfig struct {
 	mu sync.RWMutex
 
 	decoders map[string]Decoder
+	encoders map[string]Encoder
 	merge    MergeStrategy
 
 	expander  VariableExpander
 	envPrefix string
 	envExpand bool
 	data      map[string]any
+
+	// positions 记录每个配置路径来自哪个 Source 以及所在行列号
+	positions map[string]Position
+
+	// sensitive 是敏感路径 pattern，来自 WithSensitiveKeys / MarkSensitive / secret 结构体标签
+	sensitive []string
+	// secretPaths 是 Source 内容中显式标记为敏感的路径（如 YAML 的 !secret），每次 Load 重建
+	secretPaths map[string]struct{}
+	// raw 是变量替换与解密之前的配置（含覆盖层），WriteConfig 写出的是它而不是 data
+	raw map[string]any
+	// sourceSecrets 是由敏感 Source（见 Sensitive、SecretsDirSource）设置的路径，WriteConfig 拒绝写出
+	sourceSecrets map[string]struct{}
+
+	// decryptor 用于在合并后解密加密值，为 nil 时不做解密
+	decryptor Decryptor
+
+	// overrides 是通过 Set 设置的运行期覆盖层，每次 Load 后重新应用
+	overrides map[string]any
+	// fileDigests 记录已加载的本地文件内容摘要（绝对路径 => sha256），供 SafeWriteConfig 使用
+	fileDigests map[string][sha256.Size]byte
+
+	// layers 是最近一次成功 Load 的配置层列表
+	layers []LayerInfo
+	// sources 是通过 AddSource / WithSource 注册的 Source，参与每一次 Load
+	sources []Source
+	// conflictPolicy 决定合并时如何处理类型冲突
+	conflictPolicy ConflictPolicy
+	// logger 用于输出类型冲突等警告
+	logger *slog.Logger
+	// locked 是 LockKeys 锁定的路径模式
+	locked []string
+	// lockPolicy 决定覆盖被锁定路径时的处理方式
+	lockPolicy LockPolicy
+	// trust 是 RequireTrust 注册的可信级别要求
+	trust []trustRequirement
+	// directives 为 true 时处理 $replace / $delete / $append 合并指令
+	directives bool
+	// includes 为 true 时展开 FileSource 中的 $include / imports，见 WithIncludes
+	includes bool
+	// profilesEnabled 为 true 时为 FileSource 展开 profile 层，见 WithProfiles
+	profilesEnabled bool
+	// profiles 是 WithProfiles 指定的默认激活 profile
+	profiles []string
+	// profilesEnv 是读取激活 profile 的环境变量名
+	profilesEnv string
+	// profilesKey 是基础文件中声明激活 profile 的配置路径
+	profilesKey string
+	// concurrency 是 Load 时同时拉取 Source 的最大数量
+	concurrency int
+	// continueOnError 为 true 时未标记的 Source 视为 best-effort，失败不会中断 Load
+	continueOnError bool
+	// report 是最近一次 Load 的报告（无论成功与否）
+	report LoadReport
+	// statuses 是每个 Source 跨多次 Load 累计的健康状态，按首次出现顺序排列
+	statuses []*sourceStatusEntry
 }
 
 // 编译期检查接口实现
-var _ Config = (*DefaultConfig)(nil)
+var (
+	_ Config        = (*DefaultConfig)(nil)
+	_ ContextLoader = (*DefaultConfig)(nil)
+)
 
 // Option 模式
 type Option func(*DefaultConfig)
@@ -95,9 +172,16 @@ func WithVariableExpander(exp VariableExpander) Option {
 
 func NewDefaultConfig(opts ...Option) *DefaultConfig {
 	c := &DefaultConfig{
-		decoders: make(map[string]Decoder),
-		merge:    DefaultMergeStrategy{},
-		data:     make(map[string]any),
+		decoders:    make(map[string]Decoder),
+		encoders:    make(map[string]Encoder),
+		merge:       DefaultMergeStrategy{},
+		data:        make(map[string]any),
+		positions:   make(map[string]Position),
+		fileDigests: make(map[string][sha256.Size]byte),
+		concurrency: defaultLoadConcurrency,
+		logger:      slog.Default(),
+		profilesEnv: DefaultProfilesEnv,
+		profilesKey: DefaultProfilesKey,
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -105,40 +189,187 @@ func NewDefaultConfig(opts ...Option) *DefaultConfig {
 	return c
 }
 
-// Load 从多个 Source 依次加载并合并配置
+// Load 从多个 Source 依次加载并合并配置。
+// 各 Source 与已注册的 Source 一起按优先级从低到高合并（见 Prioritized），
+// 未指定优先级时按参数顺序合并，后者覆盖前者。
 func (c *DefaultConfig) Load(sources ...Source) error {
-	if len(sources) == 0 {
+	return c.LoadContext(context.Background(), sources...)
+}
+
+// LoadContext 从多个 Source 依次加载并合并配置。
+// ctx 会传递给每个 Source（未实现 ContextSource 的 Source 通过 AsContextSource 适配），
+// ctx 取消或超时后立即返回，返回的错误满足 errors.Is(err, ctx.Err())，当前配置保持不变。
+//
+// best-effort Source（见 BestEffort、WithContinueOnError）失败时不会中断加载：其余层照常合并并生效，
+// 返回的错误为 *LoadError，汇总了所有失败的 best-effort Source。
+func (c *DefaultConfig) LoadContext(ctx context.Context, sources ...Source) (err error) {
+	stack := expandLayers(ctx, c.layerStack(sources))
+	if len(stack) == 0 {
 		return nil
 	}
 
 	tmp := make(map[string]any)
+	positions := make(map[string]Position)
+	secretPaths := make(map[string]struct{})
+	sourceSecrets := make(map[string]struct{})
+	digests := make(map[string][sha256.Size]byte)
+	var (
+		layers   []LayerInfo
+		failures []error
+		profiles ProfileResolution
+	)
 
-	for _, src := range sources {
-		if src == nil {
+	c.mu.RLock()
+	patterns := c.sensitive
+	st := &mergeState{
+		positions: positions,
+		locks:     append([]string(nil), c.locked...),
+		trust:     append([]trustRequirement(nil), c.trust...),
+	}
+	c.mu.RUnlock()
+	// secretPaths 随合并逐层增长，因此 sensitive 在任意时刻反映已合并层中的敏感路径
+	sensitive := func(path string) bool {
+		return isSensitivePath(path, patterns, secretPaths)
+	}
+	st.sensitive = sensitive
+
+	// 无论成功与否都记录本次 Load 的报告
+	start := time.Now()
+	defer func() {
+		c.mu.Lock()
+		c.report = LoadReport{StartedAt: start, Duration: time.Since(start), Layers: layers, Conflicts: st.conflicts, LockViolations: st.violations, RejectedKeys: st.rejected, Profiles: profiles, Err: err}
+		c.recordStatus(stack, layers, start)
+		c.mu.Unlock()
+	}()
+
+	// 并发拉取所有 Source，再按层栈顺序依次解析与合并，保证合并结果是确定的
+	fetched := c.fetchAll(ctx, stack)
+	if c.profilesEnabled {
+		profiles = c.resolveProfiles(stack, fetched)
+		stack, fetched = c.expandProfiles(ctx, stack, fetched, profiles.Active)
+	}
+
+	for i, entry := range stack {
+		src := entry.src
+		raw, meta, err := fetched[i].data, fetched[i].meta, fetched[i].err
+		name := sourceName(src, meta)
+		info := LayerInfo{
+			Name:     name,
+			Format:   meta.Format,
+			Priority: entry.priority,
+			Bytes:    len(raw),
+			Duration: fetched[i].duration,
+			Required: sourceRequired(src, !c.continueOnError),
+			Revision: meta.Revision,
+			Profile:  sourceProfile(src),
+		}
+		// best-effort 层失败时记录错误并跳过该层，required 层失败或 ctx 结束则中断 Load
+		fail := func(err error) error {
+			info.Failed, info.Err = true, err
+			layers = append(layers, info)
+			if info.Required || ctx.Err() != nil {
+				return err
+			}
+			failures = append(failures, err)
+			return nil
+		}
+
+		var skip *SkipError
+		if errors.As(err, &skip) {
+			info.Skipped, info.Reason = true, skip.Reason.Error()
+			layers = append(layers, info)
 			continue
 		}
-		raw, meta, err := src.Load()
 		if err != nil {
-			return fmt.Errorf("load source %q failed: %w", meta.Source, err)
+			if err := fail(&SourceError{Op: "load", Source: name, Err: err}); err != nil {
+				return err
+			}
+			continue
 		}
 
-		format := strings.ToLower(meta.Format)
-		dec, ok := c.decoders[format]
-		if !ok {
-			return fmt.Errorf("no decoder registered for format: %s", format)
+		// JSON Patch 层直接作用于已合并的配置，不经过 Decoder
+		if _, ok := findSource[*JSONPatchSource](src); ok {
+			patched, err := c.applyPatchLayer(tmp, src, name, st, raw)
+			if err != nil {
+				if err := fail(&SourceError{Op: "patch", Source: name, Err: err}); err != nil {
+					return err
+				}
+				continue
+			}
+			tmp = patched
+			recordFileDigest(digests, src, raw)
+			layers = append(layers, info)
+			continue
 		}
 
-		m, err := dec.Decode(raw)
+		layer, err := c.decode(raw, meta.Format, name)
+		if err == nil && c.includes {
+			if f, ok := includeBase(src); ok {
+				if layer, err = c.resolveIncludes(f, layer); err != nil {
+					err = &SourceError{Op: "include", Source: name, Err: err}
+				}
+			}
+		}
 		if err != nil {
-			return fmt.Errorf("decode source %q failed: %w", meta.Source, err)
+			if err := fail(err); err != nil {
+				return err
+			}
+			continue
 		}
 
-		tmp, err = c.merge.Merge(tmp, m)
+		merged, err := c.mergeLayer(tmp, src, name, st, layer)
 		if err != nil {
-			return fmt.Errorf("merge source %q failed: %w", meta.Source, err)
+			if err := fail(&SourceError{Op: "merge", Source: name, Err: err}); err != nil {
+				return err
+			}
+			continue
+		}
+		tmp = merged
+		kept := savePositions(positions, layer.restored)
+		deletePositions(positions, layer.deleted)
+		recordPositions(positions, "", layer.data, name, layer.positions)
+		// 合并策略（例如 MergePatchStrategy 的 null）可能删除已记录位置的路径
+		deletePositions(positions, removedNulls("", layer.data, merged))
+		for k, v := range layer.origins {
+			if pos, ok := positions[k]; ok && pos.Source == name {
+				positions[k] = v
+			}
+		}
+		deletePositions(positions, layer.restored)
+		for k, v := range kept {
+			positions[k] = v
+		}
+		st.locks = append(st.locks, sourceLockedKeys(src, layer.data)...)
+		if sourceSensitive(src) {
+			var paths []string
+			flattenKeys("", layer.data, &paths)
+			for _, p := range paths {
+				sourceSecrets[p] = struct{}{}
+			}
+			layer.secrets = append(layer.secrets, paths...)
+		}
+		for _, p := range layer.secrets {
+			secretPaths[p] = struct{}{}
+		}
+		recordFileDigest(digests, src, raw)
+		layers = append(layers, info)
+	}
+
+	// 运行期覆盖层（Set）始终位于最上层
+	c.mu.RLock()
+	overrides := cloneMap(c.overrides)
+	c.mu.RUnlock()
+	if len(overrides) > 0 {
+		var err error
+		if tmp, err = c.merge.Merge(tmp, overrides); err != nil {
+			return &SourceError{Op: "merge", Source: overrideSourceName, Err: err}
 		}
+		recordPositions(positions, "", overrides, overrideSourceName, nil)
 	}
 
+	// 变量替换与解密之前的快照，写回文件时不会把 ${VAR}、ENC(...) 替换为明文
+	rawData := cloneMap(tmp)
+
 	// 环境变量占位符替换
 	if c.envExpand && c.expander != nil {
 		lookup := func(name string) (string, bool) {
@@ -147,23 +378,199 @@ func (c *DefaultConfig) Load(sources ...Source) error {
 			}
 			return os.LookupEnv(name)
 		}
-		if err := expandMapInPlace(tmp, c.expander, lookup); err != nil {
+		if err := expandMapInPlace(tmp, "", c.expander, lookup, sensitive); err != nil {
 			return fmt.Errorf("expand env vars failed: %w", err)
 		}
 	}
 
+	// 解密 ENC(...) / ${enc:...} 形式的加密值，解密后的路径自动视为敏感
+	if c.decryptor != nil {
+		if err := decryptMapInPlace(tmp, "", c.decryptor, secretPaths); err != nil {
+			return fmt.Errorf("decrypt values failed: %w", err)
+		}
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.data = tmp
+	c.raw = rawData
+	c.positions = positions
+	c.secretPaths = secretPaths
+	c.sourceSecrets = sourceSecrets
+	c.layers = layers
+	for k, v := range digests {
+		c.fileDigests[k] = v
+	}
+	if len(failures) > 0 {
+		return &LoadError{Errors: failures}
+	}
 	return nil
 }
 
+// decodedLayer 是单个 Source 解析后的结果。
+type decodedLayer struct {
+	data      map[string]any
+	positions decoder.Positions   // 各路径的行列号，Decoder 不支持时为 nil
+	secrets   []string            // Source 内容中显式标记为敏感的路径
+	deleted   []string            // 合并指令删除或整体替换的路径，见 WithMergeDirectives
+	restored  []string            // 被锁定而还原的路径，见 LockKeys
+	origins   map[string]Position // 来自被包含文件的路径的实际位置，见 WithIncludes
+}
+
+// mergeState 是一次 Load 中跨层共享的合并状态。
+type mergeState struct {
+	positions  map[string]Position // 已合并各路径的来源，用于冲突报告
+	locks      []string            // 当前生效的锁定路径模式
+	trust      []trustRequirement  // RequireTrust 注册的可信级别要求
+	conflicts  []TypeConflict
+	violations []LockViolation
+	rejected   []RejectedKey
+	sensitive  func(path string) bool // 判断路径是否敏感，用于错误信息脱敏
+}
+
+// mergeLayer 把一个已解析的层合并到 dst 上，返回合并结果。
+// 开启 WithMergeDirectives 时先处理合并指令：layer.data 被替换为去掉指令后的数据（用于记录位置），
+// 被删除/整体替换的路径记录在 layer.deleted 中。
+//
+// Source 带有 KeyFilter 或可信级别不足时，不允许的 key 在合并前被丢弃并记录在 st.rejected 中。
+// 类型冲突策略不为 ConflictOverride 时检测 map / list / 标量之间的覆盖；存在锁定路径时，
+// 对锁定路径的修改按 LockPolicy 拒绝或还原，被还原的路径记录在 layer.restored 中。
+func (c *DefaultConfig) mergeLayer(dst map[string]any, src Source, name string, st *mergeState, layer *decodedLayer) (map[string]any, error) {
+	// 先按 KeyFilter / 可信级别丢弃不允许该 Source 设置的 key
+	if g := newKeyGuard(src, st.trust, dst); g != nil {
+		filtered, rejected := g.filter("", layer.data, c.directives)
+		for _, r := range rejected {
+			r.Source = name
+			st.rejected = append(st.rejected, r)
+			c.logger.Warn("config key rejected", "path", r.Path, "source", name, "reason", r.Reason)
+		}
+		layer.data = filtered
+	}
+
+	var (
+		before map[string]any
+		locked []string
+	)
+	if len(st.locks) > 0 {
+		if locked = lockedPaths(dst, st.locks); len(locked) > 0 {
+			// 合并会原地修改 dst，保留原值用于比较，并保证拒绝时 dst 不受影响
+			before, dst = dst, cloneMap(dst)
+		}
+	}
+
+	data := layer.data
+	if c.directives {
+		// 指令会原地修改 dst，先拷贝一份，避免合并失败时留下半成品
+		dst = cloneMap(dst)
+		rest, deleted, err := applyMergeDirectives("", dst, layer.data)
+		if err != nil {
+			return nil, err
+		}
+		data = rest
+		layer.data = stripDirectives(layer.data).(map[string]any)
+		layer.deleted = deleted
+	}
+
+	if c.conflictPolicy != ConflictOverride {
+		conflicts := detectTypeConflicts("", dst, data, st.positions, name)
+		st.conflicts = append(st.conflicts, conflicts...)
+		if len(conflicts) > 0 && c.conflictPolicy == ConflictError {
+			return nil, &TypeConflictError{Conflicts: conflicts}
+		}
+		for _, tc := range conflicts {
+			c.logger.Warn("config type conflict, overriding",
+				"path", tc.Path,
+				"existing", tc.Existing, "existing_source", tc.ExistingSource,
+				"incoming", tc.Incoming, "incoming_source", tc.IncomingSource,
+			)
+		}
+	}
+
+	if before == nil && !c.directives {
+		// MergeStrategy 可能原地修改 dst，在副本上合并，失败时（例如 BestEffort 层）tmp 不会留下半成品
+		dst = cloneMap(dst)
+	}
+	merged, err := sourceMergeStrategy(src, c.merge).Merge(dst, data)
+	if err != nil || len(locked) == 0 {
+		return merged, err
+	}
+
+	restored, err := c.enforceLocks(before, merged, locked, name, st)
+	if err != nil {
+		return nil, err
+	}
+	layer.restored = restored
+	return merged, nil
+}
+
+// enforceLocks 比较合并前后被锁定路径的值并按 LockPolicy 处理：LockIgnore 时还原被修改的路径并返回实际恢复的路径，
+// LockReject 时返回 *LockViolationError。每次覆盖尝试都会输出警告并记录在 st.violations 中。
+func (c *DefaultConfig) enforceLocks(before, after map[string]any, locked []string, name string, st *mergeState) ([]string, error) {
+	violated, restored := checkLocks(before, after, locked, c.lockPolicy == LockIgnore)
+	var violations []LockViolation
+	for _, p := range violated {
+		violations = append(violations, LockViolation{Path: p, Source: name})
+		c.logger.Warn("attempt to override locked config key",
+			"path", p, "source", name, "locked_by", st.positions[p].Source, "rejected", c.lockPolicy == LockReject)
+	}
+	st.violations = append(st.violations, violations...)
+	if len(violations) > 0 && c.lockPolicy == LockReject {
+		return nil, &LockViolationError{Violations: violations}
+	}
+	return restored, nil
+}
+
+// decode 使用与 format 对应的 Decoder 解析原始内容。
+// 如果 Decoder 实现了 PositionDecoder / SecretDecoder，会同时收集行列号和敏感路径。
+func (c *DefaultConfig) decode(raw []byte, format, source string) (*decodedLayer, error) {
+	format = strings.ToLower(format)
+	dec, ok := c.decoders[format]
+	if !ok {
+		return nil, &DecodeError{Source: source, Format: format, Err: ErrNoDecoder}
+	}
+
+	layer := &decodedLayer{}
+	var err error
+	if pd, ok := dec.(PositionDecoder); ok {
+		layer.data, layer.positions, err = pd.DecodePositions(raw)
+	} else {
+		layer.data, err = dec.Decode(raw)
+	}
+	if err == nil {
+		if sd, ok := dec.(SecretDecoder); ok {
+			layer.secrets, err = sd.SecretPaths(raw)
+		}
+	}
+	if err != nil {
+		de := &DecodeError{Source: source, Format: format, Err: err}
+		var derr *decoder.Error
+		if errors.As(err, &derr) {
+			de.Line, de.Column = derr.Line, derr.Column
+		}
+		return nil, de
+	}
+	return layer, nil
+}
+
+// sourceName 返回 Source 的展示名称：优先使用 Metadata.Source，
+// 其次是 Source 自身的 Name()，都没有时退化为类型名。
+func sourceName(src Source, meta Metadata) string {
+	if meta.Source != "" {
+		return meta.Source
+	}
+	if n, ok := src.(interface{ Name() string }); ok && n.Name() != "" {
+		return n.Name()
+	}
+	return fmt.Sprintf("%T", src)
+}
+
 // Unmarshal 将最终配置映射到结构体
 // 实现方式: data -> JSON -> target 结构体
 func (c *DefaultConfig) Unmarshal(target any) error {
 	if target == nil {
 		return errors.New("target is nil")
 	}
+	c.markSensitiveStruct("", target)
 
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -172,16 +579,64 @@ func (c *DefaultConfig) Unmarshal(target any) error {
 		return errors.New("config is empty, call Load first")
 	}
 
-	b, err := json.Marshal(c.data)
+	return c.unmarshalValue("", c.data, target)
+}
+
+// UnmarshalKey 将 path 对应的子树映射到结构体。
+// path 不存在时返回的错误满足 errors.Is(err, ErrKeyNotFound)。
+func (c *DefaultConfig) UnmarshalKey(path string, target any) error {
+	if target == nil {
+		return errors.New("target is nil")
+	}
+	c.markSensitiveStruct(path, target)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	v, ok := getByPath(c.data, path)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, path)
+	}
+	return c.unmarshalValue(path, v, target)
+}
+
+// unmarshalValue 通过 JSON 中转把 v 映射到 target。time.Duration 字段接受 "1m30s" 形式的字符串。
+// 类型不匹配时，错误信息中会附带出错字段的来源位置（如 base.yaml:12:3）。
+// 调用方需持有读锁。
+func (c *DefaultConfig) unmarshalValue(prefix string, v any, target any) error {
+	if t := reflect.TypeOf(target); t != nil && hasDuration(t, nil) {
+		v = durationsToNanos(v, t)
+	}
+	b, err := json.Marshal(v)
 	if err != nil {
 		return fmt.Errorf("marshal config map failed: %w", err)
 	}
 	if err := json.Unmarshal(b, target); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			path := typeErr.Field
+			if prefix != "" {
+				path = prefix + "." + path
+			}
+			if pos, ok := c.positions[path]; ok {
+				return fmt.Errorf("unmarshal into target failed at %s (%s): %w", pos, path, err)
+			}
+		}
 		return fmt.Errorf("unmarshal into target failed: %w", err)
 	}
 	return nil
 }
 
+// Position 返回 path 对应的值来自哪个 Source，以及（Decoder 支持时）所在的行列号。
+// 可用于在校验失败时输出 "base.yaml:12:3" 形式的定位信息。
+func (c *DefaultConfig) Position(path string) (Position, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	pos, ok := c.positions[path]
+	return pos, ok
+}
+
 func (c *DefaultConfig) WithEnvPrefix(prefix string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
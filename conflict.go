@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// ConflictPolicy 决定合并时遇到类型冲突（map / list / 标量之间互相覆盖）如何处理。
+type ConflictPolicy int
+
+const (
+	// ConflictOverride 是默认行为：后加载的值直接覆盖，不做检测
+	ConflictOverride ConflictPolicy = iota
+	// ConflictWarn 照常覆盖，但通过 Logger 输出警告，并记录在 LoadReport.Conflicts 中
+	ConflictWarn
+	// ConflictError 把冲突视为该层的合并错误（*TypeConflictError）
+	ConflictError
+)
+
+// TypeConflict 描述一次类型冲突：Path 上已有值与新值的类型不同。
+type TypeConflict struct {
+	Path string
+	// Existing / Incoming 是双方的类型："map"、"list" 或 "scalar"
+	Existing string
+	Incoming string
+	// ExistingSource 是已有值来自的 Source，IncomingSource 是新值所在的 Source
+	ExistingSource string
+	IncomingSource string
+}
+
+func (c TypeConflict) String() string {
+	return fmt.Sprintf("%s: %s from %q conflicts with %s from %q", c.Path, c.Incoming, c.IncomingSource, c.Existing, c.ExistingSource)
+}
+
+// TypeConflictError 是 ConflictError 策略下合并失败的错误。
+type TypeConflictError struct {
+	Conflicts []TypeConflict
+}
+
+func (e *TypeConflictError) Error() string {
+	parts := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		parts[i] = c.String()
+	}
+	return "type conflict: " + strings.Join(parts, "; ")
+}
+
+// WithTypeConflictPolicy 设置类型冲突策略，例如 base.yaml 中 db 是 map，而后续 Source 把 db 设为字符串：
+//
+//	cfg := NewDefaultConfig(WithTypeConflictPolicy(ConflictError))
+//
+// 可以在调整配置结构时避免整个子树被悄悄覆盖。运行期覆盖（Set）不参与检测。
+func WithTypeConflictPolicy(p ConflictPolicy) Option {
+	return func(c *DefaultConfig) {
+		c.conflictPolicy = p
+	}
+}
+
+// WithLogger 设置 DefaultConfig 输出警告时使用的 Logger，默认为 slog.Default()。
+func WithLogger(l *slog.Logger) Option {
+	return func(c *DefaultConfig) {
+		if l != nil {
+			c.logger = l
+		}
+	}
+}
+
+// detectTypeConflicts 比较 dst 与即将合并的 src，返回类型冲突，按路径排序。
+// positions 用于查找已有值来自哪个 Source。null 值不视为冲突。
+func detectTypeConflicts(prefix string, dst, src map[string]any, positions map[string]Position, source string) []TypeConflict {
+	var out []TypeConflict
+	for k, v := range src {
+		existing, ok := dst[k]
+		if !ok || existing == nil || v == nil {
+			continue
+		}
+		path := joinPath(prefix, k)
+		ek, vk := valueKind(existing), valueKind(v)
+		if ek != vk {
+			out = append(out, TypeConflict{
+				Path:           path,
+				Existing:       ek,
+				Incoming:       vk,
+				ExistingSource: positions[path].Source,
+				IncomingSource: source,
+			})
+			continue
+		}
+		if ek == "map" {
+			m1, _ := toStringMap(existing)
+			m2, _ := toStringMap(v)
+			out = append(out, detectTypeConflicts(path, m1, m2, positions, source)...)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+func valueKind(v any) string {
+	if _, ok := toStringMap(v); ok {
+		return "map"
+	}
+	if _, ok := v.([]any); ok {
+		return "list"
+	}
+	return "scalar"
+}
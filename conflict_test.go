@@ -0,0 +1,52 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestTypeConflictPolicy(t *testing.T) {
+	base := &switchSource{data: `{"db":{"host":"x","port":1},"tags":["a"],"name":"demo"}`}
+	override := Prioritized("override.json", PriorityRemote, &switchSource{data: `{"db":"mysql://x","tags":{"k":"v"},"name":"other"}`})
+
+	t.Run("Error", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}), WithTypeConflictPolicy(ConflictError))
+		err := cfg.Load(base, override)
+
+		var tce *TypeConflictError
+		require.ErrorAs(t, err, &tce)
+		assert.Equal(t, []TypeConflict{
+			{Path: "db", Existing: "map", Incoming: "scalar", ExistingSource: "remote", IncomingSource: "override.json"},
+			{Path: "tags", Existing: "list", Incoming: "map", ExistingSource: "remote", IncomingSource: "override.json"},
+		}, tce.Conflicts)
+		assert.Contains(t, err.Error(), `db: scalar from "override.json" conflicts with map from "remote"`)
+		assert.Len(t, cfg.LoadReport().Conflicts, 2)
+	})
+
+	t.Run("Warn", func(t *testing.T) {
+		var buf bytes.Buffer
+		cfg := NewDefaultConfig(
+			WithDecoder(decoder.JSONDecoder{}),
+			WithTypeConflictPolicy(ConflictWarn),
+			WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+		)
+		require.NoError(t, cfg.Load(base, override))
+		db, _ := cfg.GetString("db")
+		assert.Equal(t, "mysql://x", db)
+		assert.Contains(t, buf.String(), "path=db")
+		assert.Contains(t, buf.String(), "incoming_source=override.json")
+		assert.Len(t, cfg.LoadReport().Conflicts, 2)
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+		require.NoError(t, cfg.Load(base, override))
+		assert.Empty(t, cfg.LoadReport().Conflicts)
+	})
+}
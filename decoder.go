@@ -1,8 +1,22 @@
 package config
 
+import "github.com/lifei6671/go-config/decoder"
+
 // Decoder 将不同格式的配置字节解析为 map[string]any 中间层结构。
 // 然后由 Config 合并多个结构、执行 env 扩展等。
 type Decoder interface {
 	Decode(data []byte) (map[string]any, error)
 	Format() string // "json" | "yaml" | "toml" ...
 }
+
+// PositionDecoder 是 Decoder 的可选扩展：除解析结果外，还返回每个路径在原始内容中的行列号。
+// 内置的 YAML / TOML / Properties Decoder 均实现了该接口。
+type PositionDecoder interface {
+	DecodePositions(data []byte) (map[string]any, decoder.Positions, error)
+}
+
+// SecretDecoder 是 Decoder 的可选扩展：返回内容中被显式标记为敏感的路径，
+// 例如 YAML 中带 !secret 标签的值。这些路径在 Redacted() 等输出中会被脱敏。
+type SecretDecoder interface {
+	SecretPaths(data []byte) ([]string, error)
+}
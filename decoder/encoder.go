@@ -0,0 +1,43 @@
+package decoder
+
+import (
+	"fmt"
+	"sort"
+)
+
+// normalizeForEncode 递归地把 map[any]any 统一为 map[string]any，
+// 保证各 Encoder 面对的都是同一种中间结构。
+func normalizeForEncode(v any) any {
+	switch vv := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(vv))
+		for k, val := range vv {
+			out[k] = normalizeForEncode(val)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(vv))
+		for k, val := range vv {
+			out[fmt.Sprint(k)] = normalizeForEncode(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(vv))
+		for i, item := range vv {
+			out[i] = normalizeForEncode(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// sortedKeys 返回 map 的有序 key 列表，用于生成稳定的输出。
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,87 @@
+package decoder
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Position 描述某个值在原始配置内容中的位置，行列号均从 1 开始。
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Positions 记录每个配置路径的位置，key 为 "a.b.c" 形式的路径。
+type Positions map[string]Position
+
+// Error 是各 Decoder 返回的解析错误，携带格式以及（可获取时的）行列号。
+// Line/Column 为 0 表示底层解析器没有提供位置信息。
+//
+// 调用方可以通过 errors.As 取出位置信息：
+//
+//	var de *decoder.Error
+//	if errors.As(err, &de) {
+//	    fmt.Println(de.Line, de.Column)
+//	}
+type Error struct {
+	Format string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("%s decode failed: line %d, column %d: %v", e.Format, e.Line, e.Column, e.Err)
+	case e.Line > 0:
+		return fmt.Sprintf("%s decode failed: line %d: %v", e.Format, e.Line, e.Err)
+	default:
+		return fmt.Sprintf("%s decode failed: %v", e.Format, e.Err)
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// offsetToPosition 将字节偏移量换算为行列号。
+func offsetToPosition(data []byte, offset int64) Position {
+	if offset < 0 {
+		return Position{}
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	lead := data[:offset]
+	return Position{
+		Line:   bytes.Count(lead, []byte{'\n'}) + 1,
+		Column: len(lead) - bytes.LastIndexByte(lead, '\n'),
+	}
+}
+
+// lineNumberPattern 用于从 "yaml: line 3: ..." 这类错误文本中提取行号。
+var lineNumberPattern = regexp.MustCompile(`line (\d+)`)
+
+// lineFromMessage 从错误文本中提取行号，提取失败返回 0。
+func lineFromMessage(msg string) int {
+	m := lineNumberPattern.FindStringSubmatch(msg)
+	if len(m) != 2 {
+		return 0
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// joinPath 拼接 "a.b" 形式的路径。
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -2,7 +2,7 @@ package decoder
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 )
 
 // JSONDecoder 实现 JSON 配置解析。
@@ -30,7 +30,24 @@ func (JSONDecoder) Decode(data []byte) (map[string]any, error) {
 
 	var out map[string]any
 	if err := json.Unmarshal(data, &out); err != nil {
-		return nil, fmt.Errorf("json decode failed: %w", err)
+		return nil, newJSONError(data, err)
 	}
 	return out, nil
 }
+
+// newJSONError 将 encoding/json 的错误包装为 *Error，
+// 并根据错误中的字节偏移量换算出行列号。
+func newJSONError(data []byte, err error) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		pos       Position
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		pos = offsetToPosition(data, syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		pos = offsetToPosition(data, typeErr.Offset)
+	}
+	return &Error{Format: "json", Line: pos.Line, Column: pos.Column, Err: err}
+}
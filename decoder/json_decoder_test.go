@@ -31,3 +31,11 @@ func TestJSONDecoder_Decode(t *testing.T) {
 		assert.Nil(t, ret)
 	})
 }
+
+func TestJSONDecoder_DecodeErrorPosition(t *testing.T) {
+	_, err := JSONDecoder{}.Decode([]byte("{\n  \"a\": 1,\n  \"b\": }"))
+	var de *Error
+	assert.ErrorAs(t, err, &de)
+	assert.Equal(t, "json", de.Format)
+	assert.Equal(t, 3, de.Line)
+}
@@ -0,0 +1,36 @@
+package decoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// JSONEncoder 是 JSONDecoder 的逆操作，将 map[string]any 编码为 JSON。
+// 输出使用两个空格缩进，key 按字典序排列（encoding/json 对 map 的默认行为），末尾带换行。
+type JSONEncoder struct{}
+
+func NewJSONEncoder() JSONEncoder {
+	return JSONEncoder{}
+}
+
+// Format 实现 Encoder 接口。
+func (JSONEncoder) Format() string {
+	return "json"
+}
+
+// Encode 实现 Encoder 接口。
+func (JSONEncoder) Encode(data map[string]any) ([]byte, error) {
+	if data == nil {
+		data = map[string]any{}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(normalizeForEncode(data)); err != nil {
+		return nil, fmt.Errorf("json encode failed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
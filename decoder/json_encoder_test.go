@@ -0,0 +1,16 @@
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONEncoder_Encode(t *testing.T) {
+	b, err := JSONEncoder{}.Encode(map[string]any{
+		"b": map[any]any{"y": 1, "x": "<a>"},
+		"a": []any{1, 2},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\": [\n    1,\n    2\n  ],\n  \"b\": {\n    \"x\": \"<a>\",\n    \"y\": 1\n  }\n}\n", string(b))
+}
@@ -33,32 +33,57 @@ func (PropertiesDecoder) Format() string {
 }
 
 // Decode 将原始 Properties 文本解析为 map[string]any
-func (PropertiesDecoder) Decode(data []byte) (map[string]any, error) {
+func (d PropertiesDecoder) Decode(data []byte) (map[string]any, error) {
 	if len(data) == 0 {
 		return map[string]any{}, nil
 	}
 
-	m, err := ParseProperties(string(data))
+	out, _, err := d.DecodePositions(data)
 	if err != nil {
-		return nil, fmt.Errorf("properties decode failed: %w", err)
+		return nil, err
+	}
+	return out, nil
+}
+
+// DecodePositions 在 Decode 的基础上额外返回每个 key 所在的行列号。
+// 对于续行，位置指向逻辑行的第一行。
+func (PropertiesDecoder) DecodePositions(data []byte) (map[string]any, Positions, error) {
+	if len(data) == 0 {
+		return map[string]any{}, Positions{}, nil
+	}
+
+	m, positions, err := parseProperties(string(data))
+	if err != nil {
+		return nil, nil, err
 	}
 
 	out := make(map[string]any, len(m))
 	for k, v := range m {
 		out[k] = v
 	}
-	return out, nil
+	return out, positions, nil
 }
 
 // ParseProperties 按 Java Properties 标准解析字符串为 map[string]string。
 // 核心参考：Java java.util.Properties 的格式兼容。
 func ParseProperties(input string) (map[string]string, error) {
+	props, _, err := parseProperties(input)
+	if err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+// parseProperties 是 ParseProperties 的实现，额外记录每个 key 的位置。
+// 返回的错误为 *Error，包含出错的行列号。
+func parseProperties(input string) (map[string]string, Positions, error) {
 	lines := splitLogicalLines(input)
 	props := make(map[string]string)
+	positions := make(Positions)
 
 	for _, line := range lines {
 		// 去除前后空白
-		trim := strings.TrimSpace(line)
+		trim := strings.TrimSpace(line.text)
 
 		if trim == "" {
 			continue
@@ -72,32 +97,50 @@ func ParseProperties(input string) (map[string]string, error) {
 			continue
 		}
 
+		pos := Position{Line: line.line, Column: line.column}
+
 		uk, err := unescape(key)
 		if err != nil {
-			return nil, fmt.Errorf("invalid key %q: %w", key, err)
+			return nil, nil, propertiesError(pos, fmt.Errorf("invalid key %q: %w", key, err))
 		}
 
 		uv, err := unescape(val)
 		if err != nil {
-			return nil, fmt.Errorf("invalid value for key %q: %w", uk, err)
+			return nil, nil, propertiesError(pos, fmt.Errorf("invalid value for key %q: %w", uk, err))
 		}
 
 		props[uk] = uv
+		positions[uk] = pos
 	}
 
-	return props, nil
+	return props, positions, nil
+}
+
+// propertiesError 构造带位置信息的 properties 解析错误。
+func propertiesError(pos Position, err error) error {
+	return &Error{Format: "properties", Line: pos.Line, Column: pos.Column, Err: err}
+}
+
+// logicalLine 是合并续行之后的一个逻辑行，line/column 指向其首个非空白字符。
+type logicalLine struct {
+	text   string
+	line   int
+	column int
 }
 
 // splitLogicalLines 处理 Java Properties 的续行特性：
 // 行尾如果是反斜杠 "\" 则续接下一行。
-func splitLogicalLines(s string) []string {
+func splitLogicalLines(s string) []logicalLine {
 	raw := strings.Split(s, "\n")
-	lines := make([]string, 0, len(raw))
+	lines := make([]logicalLine, 0, len(raw))
 
-	var buf strings.Builder
-	continuation := false
+	var (
+		buf          strings.Builder
+		continuation bool
+		start        logicalLine
+	)
 
-	for _, r := range raw {
+	for i, r := range raw {
 		line := strings.TrimRight(r, "\r")
 
 		if continuation {
@@ -105,9 +148,13 @@ func splitLogicalLines(s string) []string {
 		} else {
 			buf.Reset()
 			buf.WriteString(strings.TrimSpace(line))
+			start = logicalLine{
+				line:   i + 1,
+				column: len(line) - len(strings.TrimLeftFunc(line, unicode.IsSpace)) + 1,
+			}
 		}
 
-		if strings.HasSuffix(buf.String(), "\\") {
+		if oddTrailingBackslashes(buf.String()) {
 			continuation = true
 			// 去掉尾部反斜杠
 			tmp := buf.String()
@@ -115,40 +162,72 @@ func splitLogicalLines(s string) []string {
 			buf.WriteString(tmp[:len(tmp)-1])
 		} else {
 			continuation = false
-			lines = append(lines, buf.String())
+			start.text = buf.String()
+			lines = append(lines, start)
 		}
 	}
 
 	if continuation {
-		lines = append(lines, buf.String())
+		start.text = buf.String()
+		lines = append(lines, start)
 	}
 	return lines
 }
 
+// oddTrailingBackslashes 判断末尾连续反斜杠是否为奇数个：奇数个时最后一个是续行符或转义符，
+// 偶数个表示转义后的字面量反斜杠。
+func oddTrailingBackslashes(s string) bool {
+	n := 0
+	for i := len(s) - 1; i >= 0 && s[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
 // splitKeyValue 按 Java 规则拆分 key/value：
 // key [=|:|whitespace] value
+// 被反斜杠转义的分隔符（\=、\:、"\ "）属于 key 本身，不参与拆分。
 func splitKeyValue(s string) (string, string, bool) {
 	// 按 '=', ':' 轮询分隔
 	for i := 0; i < len(s); i++ {
 		switch s[i] {
+		case '\\':
+			i++
 		case '=', ':':
-			return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:]), true
+			return trimEscaped(s[:i]), trimEscaped(s[i+1:]), true
 		}
 	}
 
-	// 否则按第一个空白分隔
-	if idx := strings.IndexFunc(s, unicode.IsSpace); idx >= 0 {
-		return strings.TrimSpace(s[:idx]), strings.TrimSpace(s[idx+1:]), true
+	// 否则按第一个未转义的空白分隔
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if unicode.IsSpace(rune(s[i])) {
+			return trimEscaped(s[:i]), trimEscaped(s[i+1:]), true
+		}
 	}
 
 	// 整行都是 key，没有 value
 	return s, "", true
 }
 
+// trimEscaped 去掉两侧空白，但保留以 "\ " 转义的尾部空白。
+func trimEscaped(s string) string {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	t := strings.TrimRightFunc(s, unicode.IsSpace)
+	if len(t) < len(s) && oddTrailingBackslashes(t) {
+		t = s[:len(t)+1]
+	}
+	return t
+}
+
 // unescape 负责处理 Java Properties 的转义：
 //
 //	\n  \t  \r  \\
 //	\uXXXX  (unicode)
+//	\=  \:  \#  \!  "\ "  以及其它 \x 均还原为 x
 func unescape(s string) (string, error) {
 	var out strings.Builder
 	runes := []rune(s)
@@ -24,3 +24,22 @@ func TestPropertiesDecoder_Decode(t *testing.T) {
 		assert.Equal(t, val, "root")
 	})
 }
+
+func TestPropertiesDecoder_DecodePositions(t *testing.T) {
+	t.Run("PropertiesDecoder_DecodePositions_success", func(t *testing.T) {
+		b, err := os.ReadFile("../testdata/conf/abc.properties")
+		assert.NoError(t, err)
+
+		_, pos, err := PropertiesDecoder{}.DecodePositions(b)
+		assert.NoError(t, err)
+		assert.Equal(t, Position{Line: 2, Column: 1}, pos["A"])
+		assert.Equal(t, Position{Line: 26, Column: 1}, pos["multiLineKey"])
+	})
+
+	t.Run("PropertiesDecoder_Decode_ErrorPosition", func(t *testing.T) {
+		_, err := PropertiesDecoder{}.Decode([]byte("a=1\n  b=\\u12\n"))
+		var de *Error
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, Position{Line: 2, Column: 3}, Position{Line: de.Line, Column: de.Column})
+	})
+}
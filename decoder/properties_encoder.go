@@ -0,0 +1,128 @@
+package decoder
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PropertiesEncoder 是 PropertiesDecoder 的逆操作，将 map[string]any 编码为 Java Properties。
+//
+// 编码规则：
+//   - 嵌套 map 以 "." 展开为扁平 key，例如 {"db": {"host": "x"}} => db.host=x
+//   - 列表以 "[i]" 下标展开，例如 hosts[0]=a
+//   - 所有 key 按字典序输出，每行一个 key=value
+//   - 浮点数按十进制输出，不使用科学计数法（JSON 解码出的大整数不会变成 1.2e+10）
+//   - properties 无法区分 null、空 map、空列表与空字符串，遇到这些值时返回错误
+type PropertiesEncoder struct{}
+
+func NewPropertiesEncoder() PropertiesEncoder {
+	return PropertiesEncoder{}
+}
+
+// Format 实现 Encoder 接口。
+func (PropertiesEncoder) Format() string {
+	return "properties"
+}
+
+// Encode 实现 Encoder 接口。
+func (PropertiesEncoder) Encode(data map[string]any) ([]byte, error) {
+	flat := make(map[string]any)
+	if err := flattenProperties("", normalizeForEncode(data), flat); err != nil {
+		return nil, fmt.Errorf("properties encode failed: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, k := range sortedKeys(flat) {
+		buf.WriteString(escapeProperties(k, true))
+		buf.WriteByte('=')
+		buf.WriteString(escapeProperties(formatPropertiesValue(flat[k]), false))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// flattenProperties 把嵌套结构展开为扁平的 key => 标量值。
+// null、空 map、空列表写出后都会变成空字符串，无法还原，因此直接报错。
+func flattenProperties(prefix string, v any, out map[string]any) error {
+	switch vv := v.(type) {
+	case nil:
+		return fmt.Errorf("key %q: null cannot be represented in properties", prefix)
+	case map[string]any:
+		if len(vv) == 0 && prefix != "" {
+			return fmt.Errorf("key %q: empty map cannot be represented in properties", prefix)
+		}
+		for k, val := range vv {
+			if err := flattenProperties(joinPath(prefix, k), val, out); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if len(vv) == 0 {
+			return fmt.Errorf("key %q: empty list cannot be represented in properties", prefix)
+		}
+		for i, item := range vv {
+			if err := flattenProperties(fmt.Sprintf("%s[%d]", prefix, i), item, out); err != nil {
+				return err
+			}
+		}
+	default:
+		out[prefix] = v
+	}
+	return nil
+}
+
+// formatPropertiesValue 把标量格式化为字符串，浮点数不使用科学计数法。
+func formatPropertiesValue(v any) string {
+	switch vv := v.(type) {
+	case float64:
+		return strconv.FormatFloat(vv, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(vv), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// escapeProperties 按 Java Properties 规则转义。
+// key 中的分隔符（=、:、空白）以及行首的注释符需要额外转义；value 只需保留首尾空白，
+// 其中尾部空白写成 \u0020，避免被解码时的行尾裁剪吞掉。
+func escapeProperties(s string, isKey bool) string {
+	var b strings.Builder
+	trailing := len(strings.TrimRight(s, " "))
+	for i, r := range s {
+		if !isKey && r == ' ' && i >= trailing {
+			b.WriteString(`\u0020`)
+			continue
+		}
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '=', ':':
+			if isKey {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		case ' ':
+			if isKey || i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		case '#', '!':
+			if isKey && i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
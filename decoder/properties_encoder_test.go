@@ -0,0 +1,62 @@
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertiesEncoder_Encode(t *testing.T) {
+	b, err := PropertiesEncoder{}.Encode(map[string]any{
+		"db":    map[string]any{"user": "root", "port": 3306},
+		"hosts": []any{"a", "b"},
+		"msg":   "line1\nline2",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "db.port=3306\ndb.user=root\nhosts[0]=a\nhosts[1]=b\nmsg=line1\\nline2\n", string(b))
+
+	back, err := PropertiesDecoder{}.Decode(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "line1\nline2", back["msg"])
+	assert.Equal(t, "root", back["db.user"])
+}
+
+func TestPropertiesEncoder_RoundTrip(t *testing.T) {
+	in := map[string]any{
+		"db": map[string]any{
+			"k=v":    "a=b",
+			"k:v":    "c:d",
+			"k v":    " lead and trail ",
+			"#hash":  "!bang",
+			"path\\": "C:\\dir\\",
+		},
+		"big":   float64(12345678901),
+		"ratio": 0.5,
+	}
+	b, err := PropertiesEncoder{}.Encode(in)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "big=12345678901\n")
+
+	back, err := PropertiesDecoder{}.Decode(b)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"db.k=v":    "a=b",
+		"db.k:v":    "c:d",
+		"db.k v":    " lead and trail ",
+		"db.#hash":  "!bang",
+		"db.path\\": "C:\\dir\\",
+		"big":       "12345678901",
+		"ratio":     "0.5",
+	}, back)
+}
+
+func TestPropertiesEncoder_Unrepresentable(t *testing.T) {
+	for name, v := range map[string]any{
+		"null":       nil,
+		"empty map":  map[string]any{},
+		"empty list": []any{},
+	} {
+		_, err := PropertiesEncoder{}.Encode(map[string]any{"db": map[string]any{"opts": v}})
+		assert.ErrorContains(t, err, `"db.opts"`, name)
+	}
+}
@@ -1,9 +1,10 @@
 package decoder
 
 import (
-	"fmt"
+	"errors"
 
 	toml "github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
 )
 
 // TOMLDecoder 实现对 TOML 配置的解析。
@@ -27,7 +28,90 @@ func (TOMLDecoder) Decode(data []byte) (map[string]any, error) {
 
 	var out map[string]any
 	if err := toml.Unmarshal(data, &out); err != nil {
-		return nil, fmt.Errorf("toml decode failed: %w", err)
+		return nil, newTOMLError(err)
 	}
 	return out, nil
 }
+
+// DecodePositions 在 Decode 的基础上额外返回每个 key 的行列号。
+// 位置信息来自 go-toml 的底层 AST 解析器；[[array]] 表内部的 key 不记录位置。
+func (d TOMLDecoder) DecodePositions(data []byte) (map[string]any, Positions, error) {
+	out, err := d.Decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	positions := make(Positions)
+	p := unstable.Parser{}
+	p.Reset(data)
+
+	var (
+		table   string // 当前 [table] 的路径前缀
+		inArray bool   // 当前处于 [[array]] 表中
+	)
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Table:
+			table, _ = tomlKeyPath(&p, expr, "", positions)
+			inArray = false
+		case unstable.ArrayTable:
+			table, _ = tomlKeyPath(&p, expr, "", positions)
+			inArray = true
+		case unstable.KeyValue:
+			if inArray {
+				continue
+			}
+			recordTOMLKeyValue(&p, expr, table, positions)
+		}
+	}
+	if err := p.Error(); err != nil {
+		return nil, nil, newTOMLError(err)
+	}
+	return out, positions, nil
+}
+
+// recordTOMLKeyValue 记录一个 key = value 表达式的位置，内联表会递归展开。
+func recordTOMLKeyValue(p *unstable.Parser, kv *unstable.Node, prefix string, positions Positions) {
+	path, ok := tomlKeyPath(p, kv, prefix, positions)
+	if !ok {
+		return
+	}
+	value := kv.Value()
+	if value.Kind != unstable.InlineTable {
+		return
+	}
+	it := value.Children()
+	for it.Next() {
+		recordTOMLKeyValue(p, it.Node(), path, positions)
+	}
+}
+
+// tomlKeyPath 解析表达式的（可能带点号的）key，记录每一级 key 的位置，返回完整路径。
+func tomlKeyPath(p *unstable.Parser, n *unstable.Node, prefix string, positions Positions) (string, bool) {
+	path := prefix
+	found := false
+	it := n.Key()
+	for it.Next() {
+		k := it.Node()
+		path = joinPath(path, string(k.Data))
+		if _, exists := positions[path]; !exists {
+			shape := p.Shape(k.Raw)
+			positions[path] = Position{Line: shape.Start.Line, Column: shape.Start.Column}
+		}
+		found = true
+	}
+	return path, found
+}
+
+// newTOMLError 将 go-toml 的错误包装为 *Error，并尽量提取行列号。
+func newTOMLError(err error) error {
+	var (
+		decodeErr *toml.DecodeError
+		line, col int
+	)
+	if errors.As(err, &decodeErr) {
+		line, col = decodeErr.Position()
+	}
+	return &Error{Format: "toml", Line: line, Column: col, Err: err}
+}
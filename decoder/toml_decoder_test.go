@@ -31,3 +31,24 @@ func TestTOMLDecoder_Decode(t *testing.T) {
 		assert.Nil(t, ret)
 	})
 }
+
+func TestTOMLDecoder_DecodePositions(t *testing.T) {
+	t.Run("TOMLDecoder_DecodePositions_Success", func(t *testing.T) {
+		data := []byte("name = \"app\"\n\n[server]\nhost = \"0.0.0.0\"\n  db.port = 3306\nopts = { a = 1 }\n")
+		ret, pos, err := TOMLDecoder{}.DecodePositions(data)
+		assert.NoError(t, err)
+		assert.Contains(t, ret, "server")
+		assert.Equal(t, Position{Line: 1, Column: 1}, pos["name"])
+		assert.Equal(t, Position{Line: 4, Column: 1}, pos["server.host"])
+		assert.Equal(t, Position{Line: 5, Column: 6}, pos["server.db.port"])
+		assert.Equal(t, Position{Line: 6, Column: 10}, pos["server.opts.a"])
+	})
+
+	t.Run("TOMLDecoder_Decode_ErrorPosition", func(t *testing.T) {
+		_, err := TOMLDecoder{}.Decode([]byte("a = 1\nb = \n"))
+		var de *Error
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, 2, de.Line)
+		assert.Positive(t, de.Column)
+	})
+}
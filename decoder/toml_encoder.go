@@ -0,0 +1,58 @@
+package decoder
+
+import (
+	"bytes"
+	"fmt"
+
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+// TOMLEncoder 是 TOMLDecoder 的逆操作，将 map[string]any 编码为 TOML。
+// go-toml 对 map 的 key 按字典序输出；TOML 不支持 null，值为 nil 的 key 会被忽略。
+type TOMLEncoder struct{}
+
+func NewTOMLEncoder() TOMLEncoder {
+	return TOMLEncoder{}
+}
+
+// Format 实现 Encoder 接口。
+func (TOMLEncoder) Format() string {
+	return "toml"
+}
+
+// Encode 实现 Encoder 接口。
+func (TOMLEncoder) Encode(data map[string]any) ([]byte, error) {
+	if data == nil {
+		data = map[string]any{}
+	}
+
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.SetIndentTables(true)
+	if err := enc.Encode(dropNil(normalizeForEncode(data))); err != nil {
+		return nil, fmt.Errorf("toml encode failed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// dropNil 递归移除 map 中值为 nil 的 key。
+func dropNil(v any) any {
+	switch vv := v.(type) {
+	case map[string]any:
+		for k, val := range vv {
+			if val == nil {
+				delete(vv, k)
+				continue
+			}
+			vv[k] = dropNil(val)
+		}
+		return vv
+	case []any:
+		for i := range vv {
+			vv[i] = dropNil(vv[i])
+		}
+		return vv
+	default:
+		return v
+	}
+}
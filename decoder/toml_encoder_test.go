@@ -0,0 +1,28 @@
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOMLEncoder_Encode(t *testing.T) {
+	data := map[string]any{
+		"name":   "app",
+		"empty":  nil,
+		"server": map[string]any{"port": int64(8080), "host": "0.0.0.0"},
+	}
+	b, err := TOMLEncoder{}.Encode(data)
+	assert.NoError(t, err)
+
+	again, err := TOMLEncoder{}.Encode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, string(b), string(again))
+
+	back, err := TOMLDecoder{}.Decode(b)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":   "app",
+		"server": map[string]any{"port": int64(8080), "host": "0.0.0.0"},
+	}, back)
+}
@@ -28,7 +28,7 @@ func (YAMLDecoder) Decode(data []byte) (map[string]any, error) {
 
 	var raw any
 	if err := yaml.Unmarshal(data, &raw); err != nil {
-		return nil, fmt.Errorf("yaml decode failed: %w", err)
+		return nil, newYAMLError(err)
 	}
 
 	// YAML => 统一转 map[string]any
@@ -39,6 +39,62 @@ func (YAMLDecoder) Decode(data []byte) (map[string]any, error) {
 	return out, nil
 }
 
+// DecodePositions 在 Decode 的基础上额外返回每个 key 的行列号。
+// 只解析一次：先得到 yaml.Node 语法树，再从语法树解码出值。
+func (YAMLDecoder) DecodePositions(data []byte) (map[string]any, Positions, error) {
+	positions := make(Positions)
+	if len(data) == 0 {
+		return map[string]any{}, positions, nil
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, newYAMLError(err)
+	}
+
+	var raw any
+	if err := root.Decode(&raw); err != nil {
+		return nil, nil, newYAMLError(err)
+	}
+	out, ok := convertYAMLToMapStringAny(raw).(map[string]any)
+	if !ok {
+		return map[string]any{}, positions, nil
+	}
+
+	walkYAMLNode(&root, "", func(path string, key, _ *yaml.Node) {
+		positions[path] = Position{Line: key.Line, Column: key.Column}
+	})
+	return out, positions, nil
+}
+
+// walkYAMLNode 深度优先遍历 YAML 语法树中的所有映射项，
+// 对每个 key 回调 fn(path, keyNode, valueNode)。序列内部不展开。
+func walkYAMLNode(n *yaml.Node, prefix string, fn func(path string, key, value *yaml.Node)) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			walkYAMLNode(c, prefix, fn)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if key.Value == "<<" {
+				// merge key 的内容来自锚点，位置以锚点定义处为准，这里跳过
+				continue
+			}
+			path := joinPath(prefix, key.Value)
+			fn(path, key, value)
+			walkYAMLNode(value, path, fn)
+		}
+	}
+}
+
+// newYAMLError 将 yaml.v3 的错误包装为 *Error。
+// yaml.v3 只在错误文本中给出行号（"yaml: line 3: ..."），不提供列号。
+func newYAMLError(err error) error {
+	return &Error{Format: "yaml", Line: lineFromMessage(err.Error()), Err: err}
+}
+
 // convertYAMLToMapStringAny 递归转换 YAML 的 map[interface{}]interface{}。
 func convertYAMLToMapStringAny(v any) any {
 	switch vv := v.(type) {
@@ -67,3 +123,31 @@ func convertYAMLToMapStringAny(v any) any {
 		return v
 	}
 }
+
+// SecretTag 是 YAML 中标记敏感值的本地标签：
+//
+//	db:
+//	  password: !secret "p@ssw0rd"
+//
+// 标签本身不影响解析结果，只用于让上层对该值脱敏。
+const SecretTag = "!secret"
+
+// SecretPaths 返回所有带 !secret 标签的值对应的路径。
+func (YAMLDecoder) SecretPaths(data []byte) ([]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, newYAMLError(err)
+	}
+
+	var paths []string
+	walkYAMLNode(&root, "", func(path string, _, value *yaml.Node) {
+		if value.Tag == SecretTag {
+			paths = append(paths, path)
+		}
+	})
+	return paths, nil
+}
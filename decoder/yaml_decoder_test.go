@@ -24,3 +24,34 @@ func TestYAMLDecoder_Decode(t *testing.T) {
 		assert.Nil(t, ret)
 	})
 }
+
+func TestYAMLDecoder_DecodePositions(t *testing.T) {
+	t.Run("YAMLDecoder_DecodePositions_Success", func(t *testing.T) {
+		b, err := os.ReadFile("../testdata/base.yaml")
+		assert.NoError(t, err)
+		ret, pos, err := YAMLDecoder{}.DecodePositions(b)
+		assert.NoError(t, err)
+		assert.Contains(t, ret, "server")
+		assert.Equal(t, Position{Line: 3, Column: 3}, pos["server.port"])
+		assert.Equal(t, Position{Line: 9, Column: 3}, pos["features.enabled"])
+	})
+
+	t.Run("YAMLDecoder_Decode_ErrorLine", func(t *testing.T) {
+		_, err := YAMLDecoder{}.Decode([]byte("a: 1\nb: 2\nc: d: e\n"))
+		var de *Error
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "yaml", de.Format)
+		assert.Equal(t, 3, de.Line)
+	})
+}
+
+func TestYAMLDecoder_SecretPaths(t *testing.T) {
+	data := []byte("db:\n  user: root\n  password: !secret p@ss\ntokens: !secret\n  a: x\n")
+	ret, err := YAMLDecoder{}.Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "p@ss", ret["db"].(map[string]any)["password"])
+
+	paths, err := YAMLDecoder{}.SecretPaths(data)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"db.password", "tokens"}, paths)
+}
@@ -0,0 +1,39 @@
+package decoder
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLEncoder 是 YAMLDecoder 的逆操作，将 map[string]any 编码为 YAML。
+// 输出使用两个空格缩进，key 按字典序排列。
+type YAMLEncoder struct{}
+
+func NewYAMLEncoder() YAMLEncoder {
+	return YAMLEncoder{}
+}
+
+// Format 实现 Encoder 接口。
+func (YAMLEncoder) Format() string {
+	return "yaml"
+}
+
+// Encode 实现 Encoder 接口。
+func (YAMLEncoder) Encode(data map[string]any) ([]byte, error) {
+	if data == nil {
+		data = map[string]any{}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(normalizeForEncode(data)); err != nil {
+		return nil, fmt.Errorf("yaml encode failed: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("yaml encode failed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,31 @@
+package decoder
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAMLEncoder_Encode(t *testing.T) {
+	t.Run("YAMLEncoder_Encode_Sorted", func(t *testing.T) {
+		b, err := YAMLEncoder{}.Encode(map[string]any{
+			"server": map[string]any{"port": 8080, "host": "0.0.0.0"},
+			"list":   []any{"a", "b"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "list:\n  - a\n  - b\nserver:\n  host: 0.0.0.0\n  port: 8080\n", string(b))
+	})
+
+	t.Run("YAMLEncoder_Encode_RoundTrip", func(t *testing.T) {
+		raw, err := os.ReadFile("../testdata/base.yaml")
+		assert.NoError(t, err)
+		m, err := YAMLDecoder{}.Decode(raw)
+		assert.NoError(t, err)
+		b, err := YAMLEncoder{}.Encode(m)
+		assert.NoError(t, err)
+		back, err := YAMLDecoder{}.Decode(b)
+		assert.NoError(t, err)
+		assert.Equal(t, m, back)
+	})
+}
@@ -0,0 +1,297 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 加密值的两种写法：
+//
+//	password: ENC(k1:9m2f...)
+//	dsn: "mysql://root:${enc:k1:9m2f...}@tcp(127.0.0.1:3306)/app"
+//
+// 括号/花括号内部的内容（payload）原样交给 Decryptor。
+const (
+	encFuncPrefix        = "ENC("
+	encPlaceholderPrefix = "enc:"
+)
+
+// Decryptor 负责把加密值的 payload 还原为明文。
+// DefaultConfig 在合并与占位符替换之后，对所有 ENC(...) / ${enc:...} 调用 Decrypt。
+type Decryptor interface {
+	Decrypt(payload string) (string, error)
+}
+
+// Encryptor 是 Decryptor 的逆操作，返回可以直接放进 ENC(...) 的 payload。
+type Encryptor interface {
+	Encrypt(plaintext string) (string, error)
+}
+
+// WithDecryptor 设置加密值的解密器。未设置时，加密值保持原样。
+func WithDecryptor(d Decryptor) Option {
+	return func(c *DefaultConfig) {
+		if d != nil {
+			c.decryptor = d
+		}
+	}
+}
+
+// EncryptValue 是给配置作者使用的辅助函数，返回可以直接写入配置文件的 "ENC(...)" 文本。
+func EncryptValue(enc Encryptor, plaintext string) (string, error) {
+	if enc == nil {
+		return "", errors.New("encryptor is nil")
+	}
+	payload, err := enc.Encrypt(plaintext)
+	if err != nil {
+		return "", err
+	}
+	return encFuncPrefix + payload + ")", nil
+}
+
+// decryptMapInPlace 递归解密 m 中所有字符串里的加密值，并把解密过的路径记录到 decrypted。
+// 错误信息只包含路径，不包含密文或明文。
+func decryptMapInPlace(m map[string]any, prefix string, d Decryptor, decrypted map[string]struct{}) error {
+	for k, v := range m {
+		path := joinPath(prefix, k)
+		switch vv := v.(type) {
+		case string:
+			out, changed, err := decryptString(vv, d)
+			if err != nil {
+				return fmt.Errorf("decrypt value of %q failed: %w", path, err)
+			}
+			if changed {
+				m[k] = out
+				decrypted[path] = struct{}{}
+			}
+		case []any:
+			for i, item := range vv {
+				switch iv := item.(type) {
+				case string:
+					out, changed, err := decryptString(iv, d)
+					if err != nil {
+						return fmt.Errorf("decrypt value of %q failed: %w", path, err)
+					}
+					if changed {
+						vv[i] = out
+						decrypted[path] = struct{}{}
+					}
+				default:
+					if sub, ok := toStringMap(iv); ok {
+						if err := decryptMapInPlace(sub, path, d, decrypted); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		default:
+			if sub, ok := toStringMap(vv); ok {
+				if err := decryptMapInPlace(sub, path, d, decrypted); err != nil {
+					return err
+				}
+				m[k] = sub
+			}
+		}
+	}
+	return nil
+}
+
+// decryptString 替换字符串中所有 ENC(...) 与 ${enc:...}，changed 表示是否发生了替换。
+func decryptString(s string, d Decryptor) (out string, changed bool, err error) {
+	if !strings.Contains(s, encFuncPrefix) && !strings.Contains(s, "${"+encPlaceholderPrefix) {
+		return s, false, nil
+	}
+
+	var res strings.Builder
+	for {
+		i, open, closing := nextEncrypted(s)
+		if i < 0 {
+			res.WriteString(s)
+			return res.String(), changed, nil
+		}
+		begin := i + len(open)
+		end := strings.Index(s[begin:], closing)
+		if end < 0 {
+			return "", false, fmt.Errorf("encrypted value not closed, missing %q", closing)
+		}
+		plain, err := d.Decrypt(strings.TrimSpace(s[begin : begin+end]))
+		if err != nil {
+			return "", false, err
+		}
+		res.WriteString(s[:i])
+		res.WriteString(plain)
+		s = s[begin+end+len(closing):]
+		changed = true
+	}
+}
+
+// nextEncrypted 查找下一个加密值的起始位置，返回起始下标以及对应的开闭标记。
+func nextEncrypted(s string) (int, string, string) {
+	fi := strings.Index(s, encFuncPrefix)
+	pi := strings.Index(s, "${"+encPlaceholderPrefix)
+	switch {
+	case fi < 0 && pi < 0:
+		return -1, "", ""
+	case pi < 0 || (fi >= 0 && fi < pi):
+		return fi, encFuncPrefix, ")"
+	default:
+		return pi, "${" + encPlaceholderPrefix, "}"
+	}
+}
+
+// AESGCMDecryptor 是基于 AES-256-GCM 的内置 Decryptor / Encryptor 实现。
+//
+// payload 格式为 "<keyID>:<base64(nonce|ciphertext)>"，keyID 同时作为 GCM 的附加数据，
+// 防止密文被挪到其他 key 下解密。支持多把 key 以便轮换：
+//   - Encrypt 总是使用主 key（第一把）；
+//   - Decrypt 根据 payload 中的 keyID 选择 key，旧 key 仍可解密历史数据。
+//
+// key 文本格式（文件每行一个，环境变量以逗号分隔）：
+//
+//	k2:<base64 编码的 32 字节 key>
+//	k1:<base64 编码的 32 字节 key>
+type AESGCMDecryptor struct {
+	keys    map[string][]byte
+	primary string
+}
+
+var (
+	_ Decryptor = (*AESGCMDecryptor)(nil)
+	_ Encryptor = (*AESGCMDecryptor)(nil)
+)
+
+// NewAESGCMDecryptor 使用给定的 key 集合创建解密器，primary 为加密时使用的 keyID。
+// 每把 key 必须是 32 字节（AES-256）。
+func NewAESGCMDecryptor(keys map[string][]byte, primary string) (*AESGCMDecryptor, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("AESGCMDecryptor: no keys")
+	}
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("AESGCMDecryptor: primary key %q not found", primary)
+	}
+	cp := make(map[string][]byte, len(keys))
+	for id, key := range keys {
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("AESGCMDecryptor: key id %q must not contain ':'", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("AESGCMDecryptor: key %q must be 32 bytes, got %d", id, len(key))
+		}
+		cp[id] = append([]byte(nil), key...)
+	}
+	return &AESGCMDecryptor{keys: cp, primary: primary}, nil
+}
+
+// NewAESGCMDecryptorFromFile 从 key 文件加载解密器，文件中第一把 key 为主 key。
+// 空行和以 # 开头的行会被忽略。
+func NewAESGCMDecryptorFromFile(path string) (*AESGCMDecryptor, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("AESGCMDecryptor: read key file %q failed: %w", path, err)
+	}
+	return parseAESGCMKeys(strings.Split(string(b), "\n"))
+}
+
+// NewAESGCMDecryptorFromEnv 从环境变量加载解密器，多把 key 以逗号分隔，第一把为主 key。
+func NewAESGCMDecryptorFromEnv(name string) (*AESGCMDecryptor, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok || strings.TrimSpace(v) == "" {
+		return nil, fmt.Errorf("AESGCMDecryptor: env %q is empty", name)
+	}
+	return parseAESGCMKeys(strings.Split(v, ","))
+}
+
+// parseAESGCMKeys 解析 "keyID:base64key" 形式的 key 列表，省略 keyID 时以空字符串作为 keyID。
+func parseAESGCMKeys(lines []string) (*AESGCMDecryptor, error) {
+	keys := make(map[string][]byte)
+	primary := ""
+	found := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, encoded, ok := strings.Cut(line, ":")
+		if !ok {
+			id, encoded = "", line
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("AESGCMDecryptor: decode key %q failed: %w", id, err)
+		}
+		id = strings.TrimSpace(id)
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("AESGCMDecryptor: duplicate key id %q", id)
+		}
+		keys[id] = key
+		if !found {
+			primary, found = id, true
+		}
+	}
+	return NewAESGCMDecryptor(keys, primary)
+}
+
+// GenerateAESGCMKey 生成一把随机的 AES-256 key，返回其 base64 编码。
+func GenerateAESGCMKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Encrypt 使用主 key 加密 plaintext，返回 payload。
+func (d *AESGCMDecryptor) Encrypt(plaintext string) (string, error) {
+	gcm, err := newGCM(d.keys[d.primary])
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("AESGCMDecryptor: generate nonce failed: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), []byte(d.primary))
+	return d.primary + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 实现 Decryptor 接口。
+func (d *AESGCMDecryptor) Decrypt(payload string) (string, error) {
+	id, encoded, ok := strings.Cut(payload, ":")
+	if !ok {
+		id, encoded = "", payload
+	}
+	key, ok := d.keys[id]
+	if !ok {
+		return "", fmt.Errorf("AESGCMDecryptor: unknown key id %q", id)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("AESGCMDecryptor: decode payload failed: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("AESGCMDecryptor: payload too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("AESGCMDecryptor: decrypt with key %q failed: %w", id, err)
+	}
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("AESGCMDecryptor: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestAESGCMDecryptor(t *testing.T) {
+	k1, err := GenerateAESGCMKey()
+	require.NoError(t, err)
+	k2, err := GenerateAESGCMKey()
+	require.NoError(t, err)
+
+	old, err := parseAESGCMKeys([]string{"k1:" + k1})
+	require.NoError(t, err)
+	oldValue, err := EncryptValue(old, "root")
+	require.NoError(t, err)
+
+	t.Run("KeyRotation", func(t *testing.T) {
+		keyFile := filepath.Join(t.TempDir(), "keys")
+		require.NoError(t, os.WriteFile(keyFile, []byte("# primary\nk2:"+k2+"\nk1:"+k1+"\n"), 0o600))
+		d, err := NewAESGCMDecryptorFromFile(keyFile)
+		require.NoError(t, err)
+
+		payload, err := d.Encrypt("new")
+		require.NoError(t, err)
+		assert.Regexp(t, "^k2:", payload)
+
+		plain, err := d.Decrypt(oldValue[len("ENC(") : len(oldValue)-1])
+		require.NoError(t, err)
+		assert.Equal(t, "root", plain)
+	})
+
+	t.Run("FromEnv", func(t *testing.T) {
+		t.Setenv("TEST_CONFIG_KEYS", "k2:"+k2+",k1:"+k1)
+		d, err := NewAESGCMDecryptorFromEnv("TEST_CONFIG_KEYS")
+		require.NoError(t, err)
+		_, err = d.Decrypt("k3:AAAA")
+		assert.ErrorContains(t, err, "unknown key id")
+	})
+
+	t.Run("InvalidKey", func(t *testing.T) {
+		_, err := NewAESGCMDecryptor(map[string][]byte{"k": []byte("short")}, "k")
+		assert.Error(t, err)
+	})
+}
+
+func TestDefaultConfig_Decrypt(t *testing.T) {
+	key, err := GenerateAESGCMKey()
+	require.NoError(t, err)
+	d, err := parseAESGCMKeys([]string{"k1:" + key})
+	require.NoError(t, err)
+
+	password, err := EncryptValue(d, "p@ss")
+	require.NoError(t, err)
+	payload, err := d.Encrypt("token-1")
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	content := "db:\n  password: " + password + "\n  dsn: \"root:${enc:" + payload + "}@${env.DB_HOST|localhost}\"\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	cfg := NewDefaultConfig(
+		WithDecoder(decoder.YAMLDecoder{}),
+		WithVariableExpander(DefaultVariableExpander{}),
+		WithDecryptor(d),
+	)
+	cfg.EnableEnvExpand()
+	require.NoError(t, cfg.Load(NewFileSource(path)))
+
+	v, _ := cfg.GetString("db.password")
+	assert.Equal(t, "p@ss", v)
+	v, _ = cfg.GetString("db.dsn")
+	assert.Equal(t, "root:token-1@localhost", v)
+
+	assert.True(t, cfg.IsSensitive("db.password"))
+	assert.Equal(t, RedactedValue, cfg.Redacted()["db"].(map[string]any)["dsn"])
+}
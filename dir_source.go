@@ -0,0 +1,206 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// backupSuffixes 是 WithDirSourceIgnoreHidden 忽略的编辑器与包管理器备份文件后缀。
+var backupSuffixes = []string{"~", ".bak", ".orig", ".swp", ".tmp", ".rpmnew", ".rpmsave", ".dpkg-old", ".dpkg-new", ".dpkg-dist"}
+
+// DirSource 从目录中加载所有匹配的文件（conf.d 模式），每个文件按文件名的字典序成为独立的层，
+// 格式按各自的扩展名推断，因此同一目录中可以混合 YAML / JSON / TOML；无法推断格式的文件（README、.gitkeep 等）会被忽略。
+//
+// DirSource 实现了 LayeredSource，需要通过 DefaultConfig.Load 加载；目录不存在或没有匹配的文件时，
+// 目录本身作为一个层：前者返回读取错误，后者作为空层跳过。
+type DirSource struct {
+	dir       string
+	pattern   string
+	fsys      fs.FS
+	name      string
+	recursive bool
+	// ignoreHidden 为 true 时忽略隐藏文件、隐藏目录与备份文件
+	ignoreHidden bool
+}
+
+var _ LayeredSource = (*DirSource)(nil)
+
+// DirSourceOption 用于在 NewDirSource 中配置 DirSource 的可选参数。
+type DirSourceOption func(*DirSource)
+
+// WithDirSourceFS 指定文件系统实现（embed.FS、os.DirFS 等），dir 为其中的相对路径。
+func WithDirSourceFS(fsys fs.FS) DirSourceOption {
+	return func(d *DirSource) {
+		d.fsys = fsys
+	}
+}
+
+// WithDirSourceName 指定目录层的名称，只在目录不存在或为空时出现在 Layers() 中，默认为 dir。
+func WithDirSourceName(name string) DirSourceOption {
+	return func(d *DirSource) {
+		if strings.TrimSpace(name) != "" {
+			d.name = name
+		}
+	}
+}
+
+// WithDirSourceRecursive 递归加载子目录中匹配的文件，所有文件按相对路径的字典序排列。
+func WithDirSourceRecursive() DirSourceOption {
+	return func(d *DirSource) {
+		d.recursive = true
+	}
+}
+
+// WithDirSourceIgnoreHidden 忽略以 "." 开头的文件与目录，以及 *~、*.bak、*.swp、*.rpmnew、*.dpkg-old 等备份文件。
+func WithDirSourceIgnoreHidden() DirSourceOption {
+	return func(d *DirSource) {
+		d.ignoreHidden = true
+	}
+}
+
+// NewDirSource 创建目录配置源，pattern 按 path.Match 语法匹配文件名，为空时匹配所有文件：
+//
+//	// /etc/app/conf.d/10-base.yaml、20-db.json、90-local.toml 依次合并
+//	cfg.Load(
+//	    NewFileSource("/etc/app/app.yaml"),
+//	    NewDirSource("/etc/app/conf.d", "*", WithDirSourceIgnoreHidden()),
+//	)
+//
+// 每个文件的层名称为其路径，出现在 Layers()、Position() 与错误信息中。
+func NewDirSource(dir, pattern string, opts ...DirSourceOption) *DirSource {
+	d := &DirSource{dir: strings.TrimSpace(dir), pattern: strings.TrimSpace(pattern)}
+	if d.pattern == "" {
+		d.pattern = "*"
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.name == "" {
+		d.name = d.dir
+	}
+	return d
+}
+
+// Name 返回目录层的名称。
+func (d *DirSource) Name() string {
+	return d.name
+}
+
+// Files 返回匹配且能从扩展名推断格式的文件路径，按字典序排列。未指定 fs.FS 时为本地路径。
+func (d *DirSource) Files() ([]string, error) {
+	if d.dir == "" {
+		return nil, fmt.Errorf("DirSource: dir is empty")
+	}
+	if _, err := path.Match(d.pattern, ""); err != nil {
+		return nil, fmt.Errorf("DirSource: invalid pattern %q: %w", d.pattern, err)
+	}
+
+	fsys, root := d.fsys, d.dir
+	if fsys == nil {
+		fsys, root = os.DirFS(d.dir), "."
+	}
+
+	var rels []string
+	err := fs.WalkDir(fsys, root, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if !d.recursive || (d.ignoreHidden && strings.HasPrefix(name, ".")) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.ignoreHidden && isHiddenOrBackup(name) {
+			return nil
+		}
+		if ok, _ := path.Match(d.pattern, name); !ok {
+			return nil
+		}
+		// README、.gitkeep 等无法从扩展名推断格式的文件不是配置层，跳过而不是让整个 Load 失败
+		if _, err := detectFormatFromPath(name); err != nil {
+			return nil
+		}
+		rel := strings.TrimPrefix(p, root+"/")
+		if root == "." {
+			rel = p
+		}
+		rels = append(rels, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("DirSource: read dir %q failed: %w", d.dir, err)
+	}
+
+	sort.Strings(rels)
+	files := make([]string, len(rels))
+	for i, rel := range rels {
+		if d.fsys != nil {
+			files[i] = path.Join(d.dir, rel)
+		} else {
+			files[i] = filepath.Join(d.dir, filepath.FromSlash(rel))
+		}
+	}
+	return files, nil
+}
+
+// Expand 实现 LayeredSource 接口，为每个匹配的文件返回一个 FileSource。
+func (d *DirSource) Expand(ctx context.Context) ([]Source, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	files, err := d.Files()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Source, len(files))
+	for i, f := range files {
+		out[i] = NewFileSource(f, WithFileSourceFS(d.fsys))
+	}
+	return out, nil
+}
+
+// Load 实现 Source 接口。
+func (d *DirSource) Load() ([]byte, Metadata, error) {
+	return d.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。DirSource 的内容由展开出的各个文件层提供，
+// 只有目录不存在（返回错误）或没有匹配的文件（返回 *SkipError）时才会被直接调用。
+func (d *DirSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+	files, err := d.Files()
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	if len(files) == 0 {
+		return nil, Metadata{Source: d.name}, &SkipError{Source: d.name, Reason: fmt.Errorf("no files match %q in %q", d.pattern, d.dir)}
+	}
+	return nil, Metadata{}, errors.New("DirSource: contains multiple layers, load it through DefaultConfig")
+}
+
+// isHiddenOrBackup 判断文件名是否为隐藏文件或备份文件。
+func isHiddenOrBackup(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	for _, s := range backupSuffixes {
+		if strings.HasSuffix(name, s) {
+			return true
+		}
+	}
+	return false
+}
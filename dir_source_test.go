@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func confDFS() fstest.MapFS {
+	return fstest.MapFS{
+		"conf.d/10-base.yaml":      {Data: []byte("name: base\nport: 80\n")},
+		"conf.d/20-db.json":        {Data: []byte(`{"port": 8080, "db": {"host": "db"}}`)},
+		"conf.d/30-log.toml":       {Data: []byte("[log]\nlevel = \"info\"\n")},
+		"conf.d/30-log.toml~":      {Data: []byte("broken")},
+		"conf.d/.hidden.yaml":      {Data: []byte("name: hidden\n")},
+		"conf.d/sub/40-extra.yaml": {Data: []byte("name: extra\n")},
+	}
+}
+
+func TestDirSource(t *testing.T) {
+	t.Run("lexical order and one layer per file", func(t *testing.T) {
+		cfg := newFileConfig()
+		require.NoError(t, cfg.Load(NewDirSource("conf.d", "*", WithDirSourceFS(confDFS()), WithDirSourceIgnoreHidden())))
+
+		name, _ := cfg.GetString("name")
+		port, _ := cfg.GetInt("port")
+		level, _ := cfg.GetString("log.level")
+		assert.Equal(t, "base", name)
+		assert.Equal(t, 8080, port)
+		assert.Equal(t, "info", level)
+
+		var names []string
+		for _, l := range cfg.Layers() {
+			names = append(names, l.Name)
+		}
+		assert.Equal(t, []string{"conf.d/10-base.yaml", "conf.d/20-db.json", "conf.d/30-log.toml"}, names)
+
+		pos, _ := cfg.Position("db.host")
+		assert.Equal(t, "conf.d/20-db.json", pos.Source)
+	})
+
+	t.Run("recursive and pattern", func(t *testing.T) {
+		src := NewDirSource("conf.d", "*.yaml", WithDirSourceFS(confDFS()), WithDirSourceRecursive())
+		files, err := src.Files()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"conf.d/.hidden.yaml", "conf.d/10-base.yaml", "conf.d/sub/40-extra.yaml"}, files)
+
+		src = NewDirSource("conf.d", "*.yaml", WithDirSourceFS(confDFS()), WithDirSourceRecursive(), WithDirSourceIgnoreHidden())
+		files, err = src.Files()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"conf.d/10-base.yaml", "conf.d/sub/40-extra.yaml"}, files)
+	})
+
+	t.Run("decorators apply to every file", func(t *testing.T) {
+		cfg := newFileConfig()
+		src := WithKeyFilter(NewDirSource("conf.d", "*", WithDirSourceFS(confDFS()), WithDirSourceIgnoreHidden()), KeyFilter{Deny: []string{"port"}})
+		require.NoError(t, cfg.Load(Prioritized("", PriorityOverrides, src), &switchSource{data: `{"port": 1}`}))
+
+		port, _ := cfg.GetInt("port")
+		assert.Equal(t, 1, port)
+		assert.Len(t, cfg.LoadReport().RejectedKeys, 2)
+		for _, l := range cfg.Layers()[1:] {
+			assert.Equal(t, PriorityOverrides, l.Priority)
+		}
+	})
+
+	t.Run("empty and missing dir", func(t *testing.T) {
+		cfg := newFileConfig()
+		require.NoError(t, cfg.Load(NewDirSource("conf.d", "*.ini", WithDirSourceFS(confDFS()))))
+		require.Len(t, cfg.Layers(), 1)
+		assert.True(t, cfg.Layers()[0].Skipped)
+
+		err := cfg.Load(NewDirSource("missing", "*", WithDirSourceFS(confDFS())))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("stray files without a known format are skipped", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"conf.d/10-base.yaml": {Data: []byte("name: base\n")},
+			"conf.d/README":       {Data: []byte("# conf.d\n")},
+			"conf.d/NOTES.md":     {Data: []byte("notes")},
+			"conf.d/.gitkeep":     {Data: nil},
+		}
+		src := NewDirSource("conf.d", "*", WithDirSourceFS(fsys))
+		files, err := src.Files()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"conf.d/10-base.yaml"}, files)
+
+		cfg := newFileConfig()
+		require.NoError(t, cfg.Load(src))
+		name, _ := cfg.GetString("name")
+		assert.Equal(t, "base", name)
+	})
+
+	t.Run("os dir", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"name": "b"}`), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("name: a\nport: 1\n"), 0o600))
+
+		cfg := newFileConfig()
+		require.NoError(t, cfg.Load(NewDirSource(dir, "")))
+		name, _ := cfg.GetString("name")
+		assert.Equal(t, "b", name)
+		pos, _ := cfg.Position("port")
+		assert.Equal(t, filepath.Join(dir, "a.yaml"), pos.Source)
+	})
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 合并指令使用的保留 key。
+const (
+	// DirectiveReplace 为 true 时用当前 map 整体替换已有子树，而不是深度合并
+	DirectiveReplace = "$replace"
+	// DirectiveDelete 列出要从已有子树中删除的 key
+	DirectiveDelete = "$delete"
+	// DirectiveAppend 的值（list）追加到已有 list 之后
+	DirectiveAppend = "$append"
+)
+
+// WithMergeDirectives 开启合并指令：覆盖文件可以用保留 key 表达合并意图，YAML / JSON / TOML 写法一致：
+//
+//	database:            # 整体替换 database，不继承 base 中的其他字段
+//	  $replace: true
+//	  host: db.internal
+//	cache:
+//	  $delete: [redis]   # 删除继承的 cache.redis
+//	debug: null          # 删除继承的 debug
+//	plugins:
+//	  $append: [audit]   # 追加到继承的 plugins 之后
+//
+// TOML 中需要给指令 key 加引号，例如 "$replace" = true。指令 key 不会出现在最终配置中。
+// $replace 与 $append 必须位于某个 key 之下，出现在文件顶层时 Load 返回错误；
+// $append 的目标已存在时必须是 list 且不能与其他 key 并列，$replace 的值必须是 bool。
+// 未开启时指令 key 按普通 key 处理，null 值按普通值覆盖。
+func WithMergeDirectives() Option {
+	return func(c *DefaultConfig) {
+		c.directives = true
+	}
+}
+
+// applyMergeDirectives 把 src 中的合并指令作用到 dst 上（原地修改），返回去掉已处理部分与指令 key 之后、
+// 仍需交给 MergeStrategy 合并的剩余数据，以及被删除的路径。
+func applyMergeDirectives(prefix string, dst, src map[string]any) (map[string]any, []string, error) {
+	if prefix == "" {
+		// 根节点没有可替换或追加的父级，$delete 可以用于删除顶层 key
+		for _, k := range []string{DirectiveReplace, DirectiveAppend} {
+			if _, ok := src[k]; ok {
+				return nil, nil, fmt.Errorf("%s is not allowed at the root, directives must be nested under a key", k)
+			}
+		}
+	}
+
+	rest := make(map[string]any, len(src))
+	var deleted []string
+
+	if del, ok := src[DirectiveDelete]; ok {
+		keys, err := directiveKeys(prefix, del)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, k := range keys {
+			if _, ok := dst[k]; ok {
+				delete(dst, k)
+				deleted = append(deleted, joinPath(prefix, k))
+			}
+		}
+	}
+
+	for k, v := range src {
+		if isDirectiveKey(k) {
+			continue
+		}
+		path := joinPath(prefix, k)
+
+		if v == nil {
+			if _, ok := dst[k]; ok {
+				delete(dst, k)
+				deleted = append(deleted, path)
+			}
+			continue
+		}
+
+		sub, ok := toStringMap(v)
+		if !ok {
+			rest[k] = stripDirectives(v)
+			continue
+		}
+
+		if items, ok := sub[DirectiveAppend]; ok {
+			list, ok := items.([]any)
+			if !ok {
+				return nil, nil, fmt.Errorf("%s at %q must be a list, got %T", DirectiveAppend, path, items)
+			}
+			if len(sub) > 1 {
+				return nil, nil, fmt.Errorf("%s at %q cannot be combined with other keys", DirectiveAppend, path)
+			}
+			existing, ok := dst[k].([]any)
+			if !ok && dst[k] != nil {
+				return nil, nil, fmt.Errorf("%s at %q requires an existing list, got %T", DirectiveAppend, path, dst[k])
+			}
+			dst[k] = append(append(make([]any, 0, len(existing)+len(list)), existing...), stripDirectives(list).([]any)...)
+			continue
+		}
+
+		replace, ok := sub[DirectiveReplace].(bool)
+		if _, present := sub[DirectiveReplace]; present && !ok {
+			return nil, nil, fmt.Errorf("%s at %q must be a bool, got %T", DirectiveReplace, path, sub[DirectiveReplace])
+		}
+		if replace {
+			dst[k] = stripDirectives(sub)
+			deleted = append(deleted, path)
+			continue
+		}
+
+		// 普通 map：在已有子树上递归应用指令
+		if existing, ok := toStringMap(dst[k]); ok {
+			r, d, err := applyMergeDirectives(path, existing, sub)
+			if err != nil {
+				return nil, nil, err
+			}
+			dst[k] = existing
+			rest[k] = r
+			deleted = append(deleted, d...)
+			continue
+		}
+		rest[k] = stripDirectives(sub)
+	}
+	return rest, deleted, nil
+}
+
+// directiveKeys 解析 $delete 的值，支持单个字符串或字符串列表。
+func directiveKeys(prefix string, v any) ([]string, error) {
+	switch t := v.(type) {
+	case string:
+		return []string{t}, nil
+	case []any:
+		keys := make([]string, 0, len(t))
+		for _, item := range t {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s at %q must contain strings, got %T", DirectiveDelete, prefix, item)
+			}
+			keys = append(keys, s)
+		}
+		return keys, nil
+	default:
+		return nil, fmt.Errorf("%s at %q must be a string or a list, got %T", DirectiveDelete, prefix, v)
+	}
+}
+
+// stripDirectives 返回去掉指令 key 与 null 值之后的深拷贝，$append 退化为普通 list。
+func stripDirectives(v any) any {
+	if m, ok := toStringMap(v); ok {
+		if items, ok := m[DirectiveAppend]; ok {
+			return stripDirectives(items)
+		}
+		out := make(map[string]any, len(m))
+		for k, val := range m {
+			if isDirectiveKey(k) || val == nil {
+				continue
+			}
+			out[k] = stripDirectives(val)
+		}
+		return out
+	}
+	if list, ok := v.([]any); ok {
+		out := make([]any, len(list))
+		for i, item := range list {
+			out[i] = stripDirectives(item)
+		}
+		return out
+	}
+	return v
+}
+
+func isDirectiveKey(k string) bool {
+	return k == DirectiveReplace || k == DirectiveDelete || k == DirectiveAppend
+}
+
+// deletePositions 删除 paths 及其子路径的位置信息。
+func deletePositions(positions map[string]Position, paths []string) {
+	for _, p := range paths {
+		for k := range positions {
+			if k == p || strings.HasPrefix(k, p+".") {
+				delete(positions, k)
+			}
+		}
+	}
+}
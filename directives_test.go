@@ -0,0 +1,95 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestMergeDirectives(t *testing.T) {
+	base := &switchSource{data: `{
+		"database": {"host": "localhost", "port": 5432, "user": "root"},
+		"cache": {"redis": {"addr": ":6379"}, "ttl": 60},
+		"debug": true,
+		"plugins": ["auth"]
+	}`}
+
+	for _, file := range []string{"override.yaml", "override.json", "override.toml"} {
+		t.Run(file, func(t *testing.T) {
+			cfg := NewDefaultConfig(
+				WithDecoder(decoder.YAMLDecoder{}),
+				WithDecoder(decoder.JSONDecoder{}),
+				WithDecoder(decoder.TOMLDecoder{}),
+				WithMergeDirectives(),
+			)
+			require.NoError(t, cfg.Load(base, NewFileSource("testdata/directives/"+file)))
+
+			var got map[string]any
+			require.NoError(t, cfg.Unmarshal(&got))
+			want := map[string]any{
+				"database": map[string]any{"host": "db.internal"},
+				"cache":    map[string]any{"ttl": float64(60)},
+				"plugins":  []any{"auth", "audit"},
+			}
+			if file != "override.toml" {
+				// TOML 没有 null
+				assert.Equal(t, want, got)
+			} else {
+				assert.Equal(t, want["database"], got["database"])
+				assert.Equal(t, want["cache"], got["cache"])
+				assert.Equal(t, want["plugins"], got["plugins"])
+			}
+
+			_, ok := cfg.Position("database.port")
+			assert.False(t, ok)
+			pos, ok := cfg.Position("database.host")
+			require.True(t, ok)
+			assert.Equal(t, "testdata/directives/"+file, pos.Source)
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}), WithDecoder(decoder.JSONDecoder{}))
+		require.NoError(t, cfg.Load(base, NewFileSource("testdata/directives/override.yaml")))
+		replace, ok := cfg.Get("database.$replace")
+		require.True(t, ok)
+		assert.Equal(t, true, replace)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}), WithMergeDirectives())
+		err := cfg.Load(base, &switchSource{data: `{"plugins": {"$append": "x"}}`})
+		assert.ErrorContains(t, err, `$append at "plugins" must be a list`)
+
+		err = cfg.Load(base, &switchSource{data: `{"$replace": true, "app": {"name": "x"}}`})
+		assert.ErrorContains(t, err, "$replace is not allowed at the root")
+
+		// $append 不能把已有的 map 或标量静默替换为 list
+		err = cfg.Load(base, &switchSource{data: `{"database": {"$append": ["x"]}}`})
+		assert.ErrorContains(t, err, `$append at "database" requires an existing list, got map[string]interface {}`)
+		err = cfg.Load(base, &switchSource{data: `{"debug": {"$append": ["x"]}}`})
+		assert.ErrorContains(t, err, `$append at "debug" requires an existing list, got bool`)
+
+		// $append 旁边的其他 key 无处安放，不能静默丢弃
+		err = cfg.Load(base, &switchSource{data: `{"plugins": {"$append": ["x"], "extra": 1}}`})
+		assert.ErrorContains(t, err, `$append at "plugins" cannot be combined with other keys`)
+
+		err = cfg.Load(base, &switchSource{data: `{"database": {"$replace": "yes", "host": "x"}}`})
+		assert.ErrorContains(t, err, `$replace at "database" must be a bool, got string`)
+
+		// 目标不存在时 $append 等价于普通 list
+		require.NoError(t, cfg.Load(base, &switchSource{data: `{"extra": {"$append": ["x"]}}`}))
+		extra, _ := cfg.Get("extra")
+		assert.Equal(t, []any{"x"}, extra)
+	})
+
+	t.Run("RootDelete", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}), WithDecoder(decoder.JSONDecoder{}), WithMergeDirectives())
+		require.NoError(t, cfg.Load(base, &switchSource{data: `{"$delete": ["plugins"]}`}))
+		_, ok := cfg.Get("plugins")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,267 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// diskCacheEntry 是缓存文件的内容：最后一次成功 Load 的原始字节与 Metadata。
+type diskCacheEntry struct {
+	Format   string    `json:"format"`
+	Source   string    `json:"source"`
+	Revision string    `json:"revision,omitempty"`
+	SavedAt  time.Time `json:"saved_at"`
+	Data     []byte    `json:"data"`
+}
+
+func (e *diskCacheEntry) metadata() Metadata {
+	return Metadata{Format: e.Format, Source: e.Source, Revision: e.Revision}
+}
+
+// DiskCacheSource 是 WithDiskCache 返回的装饰器，把远程 Source 最后一次成功的结果持久化到磁盘，
+// 后端不可用时使用缓存（last-known-good）。
+type DiskCacheSource struct {
+	src      Source
+	path     string
+	perm     os.FileMode
+	deadline time.Duration
+
+	mu       sync.RWMutex
+	started  bool // 是否已经完成过一次 Load（启动截止时间只作用于第一次）
+	stale    bool
+	cachedAt time.Time
+	lastErr  error
+	// failingSince 是后端连续失败开始的时间，使用缓存兜底时同样计入，后端成功后清空
+	failingSince time.Time
+}
+
+var _ ContextSource = (*DiskCacheSource)(nil)
+
+// DiskCacheOption 用于配置 DiskCacheSource。
+type DiskCacheOption func(*DiskCacheSource)
+
+// WithDiskCacheFile 指定缓存文件名（相对于缓存目录），默认根据 Source 名称的哈希生成。
+// 同一目录下缓存多个 Source 且名称可能相同时需要显式指定。
+func WithDiskCacheFile(name string) DiskCacheOption {
+	return func(d *DiskCacheSource) {
+		if name != "" {
+			d.path = filepath.Join(filepath.Dir(d.path), name)
+		}
+	}
+}
+
+// WithDiskCacheStartupDeadline 设置启动截止时间：第一次 Load 时如果后端在 timeout 内没有返回，
+// 且存在缓存，则直接使用缓存启动，不再等待后端。没有缓存时仍然等待后端结果。
+func WithDiskCacheStartupDeadline(timeout time.Duration) DiskCacheOption {
+	return func(d *DiskCacheSource) {
+		d.deadline = timeout
+	}
+}
+
+// WithDiskCachePerm 设置缓存文件权限，默认 0600。
+func WithDiskCachePerm(perm os.FileMode) DiskCacheOption {
+	return func(d *DiskCacheSource) {
+		d.perm = perm
+	}
+}
+
+// WithDiskCache 为 src 增加磁盘缓存：
+//
+//	src := WithDiskCache(NewHTTPSource(url), "/var/cache/myapp",
+//	    WithDiskCacheStartupDeadline(3*time.Second),
+//	)
+//
+// 每次成功 Load 后原子地把原始内容与 Metadata 写入 dir 下的缓存文件；后端失败时返回缓存内容，
+// 并通过 Stale 报告当前数据来自缓存。内容不存在（ErrSourceNotFound）与 ctx 取消不会回退到缓存。
+//
+// 注意缓存中保存的是原始字节，可能包含敏感信息，缓存目录应只对当前进程用户可读。
+func WithDiskCache(src Source, dir string, opts ...DiskCacheOption) *DiskCacheSource {
+	sum := sha256.Sum256([]byte(sourceName(src, Metadata{})))
+	d := &DiskCacheSource{
+		src:  src,
+		path: filepath.Join(dir, hex.EncodeToString(sum[:8])+".cache"),
+		perm: 0o600,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Name 返回被包装 Source 的名称。
+func (d *DiskCacheSource) Name() string {
+	return sourceName(d.src, Metadata{})
+}
+
+// Unwrap 返回被包装的原始 Source。
+func (d *DiskCacheSource) Unwrap() Source {
+	return d.src
+}
+
+// Path 返回缓存文件路径。
+func (d *DiskCacheSource) Path() string {
+	return d.path
+}
+
+// Stale 报告最近一次 Load 返回的是否是缓存数据（后端失败或超过启动截止时间）。
+func (d *DiskCacheSource) Stale() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.stale
+}
+
+// CachedAt 返回当前缓存的写入时间，没有缓存时为零值。
+func (d *DiskCacheSource) CachedAt() time.Time {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.cachedAt
+}
+
+// LastError 返回最近一次后端失败的错误，后端成功后清空。
+func (d *DiskCacheSource) LastError() error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.lastErr
+}
+
+// Load 实现 Source 接口。
+func (d *DiskCacheSource) Load() ([]byte, Metadata, error) {
+	return d.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (d *DiskCacheSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	d.mu.Lock()
+	first := !d.started
+	d.started = true
+	d.mu.Unlock()
+
+	var (
+		data []byte
+		meta Metadata
+		err  error
+	)
+	if first && d.deadline > 0 {
+		var fromCache bool
+		data, meta, fromCache, err = d.loadWithDeadline(ctx)
+		if fromCache {
+			return data, meta, nil
+		}
+	} else {
+		data, meta, err = loadSource(ctx, d.src)
+	}
+
+	if err == nil {
+		d.store(data, meta)
+		return data, meta, nil
+	}
+	if isNotFound(err) || errors.Is(err, context.Canceled) {
+		return nil, meta, err
+	}
+	return d.fallback(err)
+}
+
+// loadWithDeadline 在启动截止时间内等待后端；超时且缓存可用时返回缓存，并取消后端请求。
+func (d *DiskCacheSource) loadWithDeadline(ctx context.Context) ([]byte, Metadata, bool, error) {
+	type result struct {
+		data []byte
+		meta Metadata
+		err  error
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch := make(chan result, 1)
+	go func() {
+		data, meta, err := loadSource(ctx, d.src)
+		ch <- result{data, meta, err}
+	}()
+
+	timer := time.NewTimer(d.deadline)
+	defer timer.Stop()
+	select {
+	case r := <-ch:
+		return r.data, r.meta, false, r.err
+	case <-timer.C:
+	}
+
+	entry, err := d.read()
+	if err != nil {
+		// 没有可用缓存，只能继续等待后端
+		r := <-ch
+		return r.data, r.meta, false, r.err
+	}
+	d.setState(true, entry.SavedAt, fmt.Errorf("startup deadline %s exceeded", d.deadline))
+	d.setFailing(true)
+	return entry.Data, entry.metadata(), true, nil
+}
+
+// fallback 在后端失败时返回缓存内容；缓存也不可用时返回后端错误。
+func (d *DiskCacheSource) fallback(loadErr error) ([]byte, Metadata, error) {
+	d.setFailing(true)
+	entry, err := d.read()
+	if err != nil {
+		d.setState(false, time.Time{}, loadErr)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, Metadata{}, loadErr
+		}
+		return nil, Metadata{}, fmt.Errorf("%w (disk cache unavailable: %v)", loadErr, err)
+	}
+	d.setState(true, entry.SavedAt, loadErr)
+	return entry.Data, entry.metadata(), nil
+}
+
+// store 把成功结果写入缓存。写缓存失败不影响本次 Load，只记录在 LastError 中。
+func (d *DiskCacheSource) store(data []byte, meta Metadata) {
+	d.setFailing(false)
+	entry := diskCacheEntry{Format: meta.Format, Source: meta.Source, Revision: meta.Revision, SavedAt: time.Now(), Data: data}
+	raw, err := json.Marshal(entry)
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(d.path), 0o700); err == nil {
+			err = writeFileAtomic(d.path, raw, d.perm)
+		}
+	}
+	if err != nil {
+		d.setState(false, d.CachedAt(), fmt.Errorf("write disk cache %q failed: %w", d.path, err))
+		return
+	}
+	d.setState(false, entry.SavedAt, nil)
+}
+
+func (d *DiskCacheSource) read() (*diskCacheEntry, error) {
+	raw, err := os.ReadFile(d.path)
+	if err != nil {
+		return nil, err
+	}
+	var entry diskCacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, fmt.Errorf("corrupted disk cache %q: %w", d.path, err)
+	}
+	return &entry, nil
+}
+
+func (d *DiskCacheSource) setState(stale bool, cachedAt time.Time, err error) {
+	d.mu.Lock()
+	d.stale, d.cachedAt, d.lastErr = stale, cachedAt, err
+	d.mu.Unlock()
+}
+
+// setFailing 记录后端失败（failed 为 true）或恢复。只记录连续失败的起点。
+func (d *DiskCacheSource) setFailing(failed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case !failed:
+		d.failingSince = time.Time{}
+	case d.failingSince.IsZero():
+		d.failingSince = time.Now()
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// switchSource 返回当前设置的内容或错误，用于模拟后端恢复/故障。
+type switchSource struct {
+	data string
+	err  error
+}
+
+func (s *switchSource) Name() string { return "remote" }
+
+func (s *switchSource) Load() ([]byte, Metadata, error) {
+	if s.err != nil {
+		return nil, Metadata{}, s.err
+	}
+	return []byte(s.data), Metadata{Format: "json", Source: "remote"}, nil
+}
+
+func TestDiskCacheSource(t *testing.T) {
+	dir := t.TempDir()
+	backend := &switchSource{data: `{"v":1}`}
+
+	src := WithDiskCache(backend, dir)
+	data, meta, err := src.Load()
+	require.NoError(t, err)
+	assert.Equal(t, `{"v":1}`, string(data))
+	assert.False(t, src.Stale())
+	assert.FileExists(t, src.Path())
+	info, err := os.Stat(src.Path())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// 模拟进程重启 + 后端故障：新的 DiskCacheSource 从磁盘读取缓存
+	backend.err = &HTTPStatusError{URL: "http://cc", StatusCode: 503}
+	restarted := WithDiskCache(backend, dir)
+	data, meta2, err := restarted.Load()
+	require.NoError(t, err)
+	assert.Equal(t, `{"v":1}`, string(data))
+	assert.Equal(t, meta, meta2)
+	assert.True(t, restarted.Stale())
+	assert.False(t, restarted.CachedAt().IsZero())
+	assert.ErrorContains(t, restarted.LastError(), "503")
+
+	// 后端恢复后不再是陈旧数据
+	backend.err, backend.data = nil, `{"v":2}`
+	data, _, err = restarted.Load()
+	require.NoError(t, err)
+	assert.Equal(t, `{"v":2}`, string(data))
+	assert.False(t, restarted.Stale())
+	assert.NoError(t, restarted.LastError())
+
+	// 内容不存在不回退到缓存
+	backend.err = ErrSourceNotFound
+	_, _, err = restarted.Load()
+	assert.ErrorIs(t, err, ErrSourceNotFound)
+}
+
+func TestDiskCacheSource_NoCache(t *testing.T) {
+	boom := errors.New("boom")
+	src := WithDiskCache(&switchSource{err: boom}, t.TempDir())
+	_, _, err := src.Load()
+	assert.ErrorIs(t, err, boom)
+	assert.False(t, src.Stale())
+}
+
+func TestDiskCacheSource_StartupDeadline(t *testing.T) {
+	dir := t.TempDir()
+	_, _, err := WithDiskCache(&switchSource{data: `{"a":0}`}, dir, WithDiskCacheFile("blocking.cache")).Load()
+	require.NoError(t, err)
+
+	backend := blockingSource{release: make(chan struct{})}
+	defer close(backend.release)
+	src := WithDiskCache(backend, dir,
+		WithDiskCacheFile("blocking.cache"),
+		WithDiskCacheStartupDeadline(20*time.Millisecond),
+	)
+
+	start := time.Now()
+	data, _, err := src.LoadContext(context.Background())
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, `{"a":0}`, string(data))
+	assert.True(t, src.Stale())
+}
@@ -0,0 +1,8 @@
+package config
+
+// Encoder 是 Decoder 的逆操作：将 map[string]any 中间层结构编码为某种格式的字节。
+// 用于导出最终生效的配置，或在不同格式之间转换。
+type Encoder interface {
+	Encode(data map[string]any) ([]byte, error)
+	Format() string // "json" | "yaml" | "toml" ...
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -192,6 +193,11 @@ func NewEnvSource(opts ...EnvSourceOption) *EnvSource {
 	return es
 }
 
+// Name 返回该 Source 的逻辑名称，与 Metadata.Source 一致。
+func (es *EnvSource) Name() string {
+	return es.name
+}
+
 // Load 实现 Source 接口，负责：
 //
 //  1. 从 environ() 中获取所有环境变量（形式为 "KEY=VALUE" 的字符串）
@@ -207,6 +213,14 @@ func NewEnvSource(opts ...EnvSourceOption) *EnvSource {
 // 注意：EnvSource 只会覆盖它生成的 key，合并规则由 MergeStrategy 决定
 // （默认策略是后加载的 Source 覆盖前面的同名 key）。
 func (es *EnvSource) Load() ([]byte, Metadata, error) {
+	return es.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。读取环境变量不会阻塞，只在开始前检查 ctx。
+func (es *EnvSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
 	envList := es.environ()
 	root := make(map[string]any)
 
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+)
+
+var (
+	// ErrKeyNotFound 表示请求的配置路径不存在。
+	ErrKeyNotFound = errors.New("config key not found")
+
+	// ErrNoDecoder 表示 Source 返回的格式没有注册对应的 Decoder。
+	ErrNoDecoder = errors.New("no decoder registered for format")
+
+	// ErrSourceNotFound 表示 Source 指向的内容不存在，例如 HTTP 404、etcd key 不存在。
+	// 本地文件不存在时返回的错误满足 errors.Is(err, fs.ErrNotExist)。
+	ErrSourceNotFound = errors.New("source not found")
+
+	// ErrNoEncoder 表示导出配置时没有注册对应格式的 Encoder。
+	ErrNoEncoder = errors.New("no encoder registered for format")
+)
+
+// SourceError 描述某个 Source 在加载流程中某一阶段的失败。
+// Op 表示失败的阶段，例如 "load"、"merge"。
+//
+// 可以通过 errors.As 获取出错的 Source 名称：
+//
+//	var se *SourceError
+//	if errors.As(err, &se) {
+//	    log.Printf("source %s failed: %v", se.Source, se.Err)
+//	}
+type SourceError struct {
+	Op     string
+	Source string
+	Err    error
+}
+
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("%s source %q failed: %v", e.Op, e.Source, e.Err)
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError 描述某个 Source 的内容解析失败。
+// 当 Decoder 能提供位置信息时（见 decoder.Error），Line/Column 为出错的行列号，否则为 0。
+// 没有注册对应格式的 Decoder 时，Err 为 ErrNoDecoder。
+type DecodeError struct {
+	Source string
+	Format string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	if errors.Is(e.Err, ErrNoDecoder) {
+		return fmt.Sprintf("decode source %q failed: %v: %s", e.Source, e.Err, e.Format)
+	}
+	if e.Line > 0 {
+		pos := Position{Source: e.Source, Line: e.Line, Column: e.Column}
+		return fmt.Sprintf("decode source %q failed at %s: %v", e.Source, pos, e.Err)
+	}
+	return fmt.Sprintf("decode source %q failed: %v", e.Source, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Position 返回出错位置，便于统一输出 "base.yaml:12:3" 形式的定位信息。
+func (e *DecodeError) Position() Position {
+	return Position{Source: e.Source, Line: e.Line, Column: e.Column}
+}
+
+// HTTPStatusError 表示 HTTP 类 Source（HTTPSource、Apollo）收到了非预期的状态码。
+// 状态码为 404 时满足 errors.Is(err, ErrSourceNotFound)。
+// URL 保留完整地址供程序判断；Error() 只输出 scheme://host/path，避免 query 或 userinfo 中的 token 进入日志。
+type HTTPStatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d from %s", e.StatusCode, redactURL(e.URL))
+}
+
+// redactURL 去掉 URL 中的 userinfo、query 与 fragment；无法解析时不输出原文。
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "<invalid url>"
+	}
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String()
+}
+
+// Is 让 404 与 ErrSourceNotFound 匹配。
+func (e *HTTPStatusError) Is(target error) bool {
+	return target == ErrSourceNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// isNotFound 判断 err 是否表示 Source 内容不存在。
+func isNotFound(err error) bool {
+	return errors.Is(err, ErrSourceNotFound) || errors.Is(err, fs.ErrNotExist)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -116,9 +117,20 @@ func NewEtcdSource(cli *clientv3.Client, key string, opts ...EtcdSourceOption) *
 	return es
 }
 
+// Name 返回该 Source 的逻辑名称，与 Metadata.Source 一致。
+func (es *EtcdSource) Name() string {
+	return es.name
+}
+
 // Load 实现 Source 接口：从 etcd 读取一个 key 的 value 作为配置内容。
 // 返回：value 字节内容 + Metadata{Format, Source}。
 func (es *EtcdSource) Load() ([]byte, Metadata, error) {
+	return es.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+// 读超时（readTimeout）基于传入的 ctx 派生，ctx 更早结束时以 ctx 为准。
+func (es *EtcdSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
 	if es.cli == nil {
 		return nil, Metadata{}, fmt.Errorf("EtcdSource: client is nil")
 	}
@@ -126,7 +138,7 @@ func (es *EtcdSource) Load() ([]byte, Metadata, error) {
 		return nil, Metadata{}, fmt.Errorf("EtcdSource: key is empty")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), es.readTimeout)
+	ctx, cancel := context.WithTimeout(ctx, es.readTimeout)
 	defer cancel()
 
 	resp, err := es.cli.Get(ctx, es.key)
@@ -134,7 +146,7 @@ func (es *EtcdSource) Load() ([]byte, Metadata, error) {
 		return nil, Metadata{}, fmt.Errorf("EtcdSource: get key %q failed: %w", es.key, err)
 	}
 	if len(resp.Kvs) == 0 {
-		return nil, Metadata{}, fmt.Errorf("EtcdSource: key %q not found", es.key)
+		return nil, Metadata{}, fmt.Errorf("EtcdSource: key %q: %w", es.key, ErrSourceNotFound)
 	}
 
 	// etcd 支持同一个 key 多个版本，这里取最后一个版本。
@@ -153,8 +165,9 @@ func (es *EtcdSource) Load() ([]byte, Metadata, error) {
 	}
 
 	meta := Metadata{
-		Format: format,
-		Source: es.name,
+		Format:   format,
+		Source:   es.name,
+		Revision: strconv.FormatInt(kv.ModRevision, 10),
 	}
 	return data, meta, nil
 }
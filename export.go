@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"io"
+)
+
+// WithEncoder 注册一个 Encoder，用于 MarshalTo / WriteTo 等导出操作。
+func WithEncoder(enc Encoder) Option {
+	return func(c *DefaultConfig) {
+		if enc == nil {
+			return
+		}
+		if c.encoders == nil {
+			c.encoders = make(map[string]Encoder)
+		}
+		format := normalizeFormat(enc.Format())
+		if format != "" {
+			c.encoders[format] = enc
+		}
+	}
+}
+
+// MarshalOption 用于配置 MarshalTo / WriteTo 的行为。
+type MarshalOption func(*marshalOptions)
+
+type marshalOptions struct {
+	unredacted bool
+}
+
+// WithoutRedaction 导出真实值而不是 RedactedValue，用于格式转换等需要完整配置的场景。
+// 输出中可能包含密码等敏感值，不要写入日志。
+func WithoutRedaction() MarshalOption {
+	return func(o *marshalOptions) {
+		o.unredacted = true
+	}
+}
+
+// MarshalTo 将当前生效的配置编码为指定格式，常用于启动时打印最终配置或格式转换：
+//
+//	cfg := NewDefaultConfig(
+//	    WithDecoder(decoder.YAMLDecoder{}),
+//	    WithEncoder(decoder.JSONEncoder{}),
+//	)
+//	b, _ := cfg.MarshalTo("json")
+//
+// 输出是确定性的：key 按字典序排列，格式稳定，可直接用于 diff。
+// 敏感值（见 WithSensitiveKeys）默认被替换为 RedactedValue，因此默认输出不适合用于格式转换；
+// 需要真实值时传入 WithoutRedaction()：
+//
+//	b, _ := cfg.MarshalTo("toml", WithoutRedaction())
+//
+// 未注册对应格式的 Encoder 时返回的错误满足 errors.Is(err, ErrNoEncoder)。
+func (c *DefaultConfig) MarshalTo(format string, opts ...MarshalOption) ([]byte, error) {
+	var o marshalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.unredacted {
+		c.mu.RLock()
+		data := cloneMap(c.data)
+		c.mu.RUnlock()
+		if data == nil {
+			data = make(map[string]any)
+		}
+		return c.encode(format, data)
+	}
+	return c.encode(format, c.Redacted())
+}
+
+// WriteTo 将 MarshalTo 的结果写入 w，返回写入的字节数。
+func (c *DefaultConfig) WriteTo(w io.Writer, format string, opts ...MarshalOption) (int64, error) {
+	b, err := c.MarshalTo(format, opts...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// encode 使用与 format 对应的 Encoder 编码 data。
+func (c *DefaultConfig) encode(format string, data map[string]any) ([]byte, error) {
+	format = normalizeFormat(format)
+
+	c.mu.RLock()
+	enc, ok := c.encoders[format]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoEncoder, format)
+	}
+
+	b, err := enc.Encode(data)
+	if err != nil {
+		return nil, fmt.Errorf("encode config as %s failed: %w", format, err)
+	}
+	return b, nil
+}
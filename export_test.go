@@ -0,0 +1,40 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestDefaultConfig_MarshalTo(t *testing.T) {
+	cfg := NewDefaultConfig(
+		WithDecoder(decoder.JSONDecoder{}),
+		WithEncoder(decoder.JSONEncoder{}),
+		WithEncoder(decoder.YAMLEncoder{}),
+		WithSensitiveKeys("db.password"),
+	)
+	require.NoError(t, cfg.Load(NewEnvSource(WithEnvSourceEnviron(func() []string {
+		return []string{"DB__HOST=127.0.0.1", "DB__PASSWORD=root", "APP=demo"}
+	}))))
+
+	b, err := cfg.MarshalTo("yml")
+	require.NoError(t, err)
+	assert.Equal(t, "app: demo\ndb:\n  host: 127.0.0.1\n  password: '******'\n", string(b))
+
+	var buf bytes.Buffer
+	n, err := cfg.WriteTo(&buf, "json")
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Contains(t, buf.String(), `"host": "127.0.0.1"`)
+
+	b, err = cfg.MarshalTo("yaml", WithoutRedaction())
+	require.NoError(t, err)
+	assert.Equal(t, "app: demo\ndb:\n  host: 127.0.0.1\n  password: root\n", string(b))
+
+	_, err = cfg.MarshalTo("toml")
+	assert.ErrorIs(t, err, ErrNoEncoder)
+}
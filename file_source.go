@@ -1,6 +1,8 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -35,6 +37,9 @@ type FileSource struct {
 	// name 是该 Source 的标识，用于 Metadata.Source。
 	// 如果为空，则默认使用 path。
 	name string
+
+	// optional 为 true 时，文件不存在不视为错误，而是作为空层跳过。
+	optional bool
 }
 
 // FileSourceOption 用于在 NewFileSource 中配置 FileSource 的可选参数。
@@ -86,6 +91,16 @@ func WithFileSourceName(name string) FileSourceOption {
 	}
 }
 
+// WithFileSourceOptional 将文件标记为可选：文件不存在时 Load 返回 *SkipError，
+// DefaultConfig 会把它当作空层跳过。等价于 Optional(NewFileSource(path))。
+//
+// 只有“文件不存在”会被忽略，权限不足等其他读取错误仍然会返回。
+func WithFileSourceOptional() FileSourceOption {
+	return func(fs *FileSource) {
+		fs.optional = true
+	}
+}
+
 // NewFileSource 创建一个基于文件的配置源。
 //
 // path 参数：
@@ -132,6 +147,11 @@ func NewFileSource(path string, opts ...FileSourceOption) *FileSource {
 	return source
 }
 
+// Name 返回该 Source 的逻辑名称，与 Metadata.Source 一致。
+func (f *FileSource) Name() string {
+	return f.name
+}
+
 // Load 实现 Source 接口，负责：
 //
 //  1. 从指定文件系统读取文件内容到内存
@@ -143,13 +163,24 @@ func NewFileSource(path string, opts ...FileSourceOption) *FileSource {
 //   - meta: 包含 Format 和 Source 名称的元信息
 //   - err : 读取失败或格式推断失败时返回错误
 func (f *FileSource) Load() ([]byte, Metadata, error) {
+	return f.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。本地文件读取很快，只在读取前检查 ctx 是否已结束。
+func (f *FileSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
 	if f.path == "" {
 		return nil, Metadata{}, fmt.Errorf("FileSource: path is empty")
 	}
 
 	// 1. 读取文件原始内容
-	data, err := f.readFile()
+	data, err := f.readFile(f.path)
 	if err != nil {
+		if f.optional && errors.Is(err, fs.ErrNotExist) {
+			return nil, Metadata{Source: f.name}, &SkipError{Source: f.name, Reason: err}
+		}
 		return nil, Metadata{}, err
 	}
 
@@ -170,28 +201,28 @@ func (f *FileSource) Load() ([]byte, Metadata, error) {
 	return data, meta, nil
 }
 
-// readFile 负责真正的文件读取逻辑。
+// readFile 负责真正的文件读取逻辑，p 为 f.path 或被 $include 引用的文件。
 // 如果配置了 fs.FS，则使用 fsys.Open；否则使用 os.ReadFile。
-func (f *FileSource) readFile() ([]byte, error) {
+func (f *FileSource) readFile(p string) ([]byte, error) {
 	// 情况一：未配置 fs.FS，直接用操作系统文件系统
 	if f.fsys == nil {
-		b, err := os.ReadFile(f.path)
+		b, err := os.ReadFile(p)
 		if err != nil {
-			return nil, fmt.Errorf("FileSource: read file %q failed: %w", f.path, err)
+			return nil, fmt.Errorf("FileSource: read file %q failed: %w", p, err)
 		}
 		return b, nil
 	}
 
 	// 情况二：使用抽象文件系统 fs.FS
-	file, err := f.fsys.Open(f.path)
+	file, err := f.fsys.Open(p)
 	if err != nil {
-		return nil, fmt.Errorf("FileSource: open file %q from fs.FS failed: %w", f.path, err)
+		return nil, fmt.Errorf("FileSource: open file %q from fs.FS failed: %w", p, err)
 	}
 	defer file.Close()
 
 	b, err := io.ReadAll(file)
 	if err != nil {
-		return nil, fmt.Errorf("FileSource: read file %q from fs.FS failed: %w", f.path, err)
+		return nil, fmt.Errorf("FileSource: read file %q from fs.FS failed: %w", p, err)
 	}
 	return b, nil
 }
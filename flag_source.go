@@ -0,0 +1,316 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FlagSource 把命令行中显式设置的 flag 转换为配置层，默认位于 PriorityFlags 槽位，
+// 因此 flag、环境变量与配置文件共享同一条优先级链：
+//
+//	defaults < files < remote < env < flags < overrides
+//
+// 没有在命令行中出现的 flag 不会设置任何 key，它们的默认值不会覆盖文件或环境变量中的值。
+type FlagSource struct {
+	fs      *flag.FlagSet
+	mapping map[string]string
+	name    string
+	auto    bool
+}
+
+var _ ContextSource = (*FlagSource)(nil)
+
+// FlagSourceOption 用于在 NewFlagSource 中配置 FlagSource 的可选参数。
+type FlagSourceOption func(*FlagSource)
+
+// WithFlagSourceName 指定该 Source 的名称，默认为 "flags"。
+func WithFlagSourceName(name string) FlagSourceOption {
+	return func(f *FlagSource) {
+		if strings.TrimSpace(name) != "" {
+			f.name = name
+		}
+	}
+}
+
+// WithFlagSourceAutoMapping 把 mapping 中没有列出的 flag 按名称自动映射：
+// "-" 表示层级，例如 --db-host => db.host。未开启时没有映射的 flag 被忽略。
+func WithFlagSourceAutoMapping() FlagSourceOption {
+	return func(f *FlagSource) {
+		f.auto = true
+	}
+}
+
+// NewFlagSource 创建命令行 flag 配置源，mapping 为 flag 名称到配置路径的映射：
+//
+//	fs := flag.NewFlagSet("app", flag.ExitOnError)
+//	mapping, _ := DefineFlags(fs, &AppConfig{})   // 或 DefineFlagKeys / 手写 fs.Int("port", ...)
+//	_ = fs.Parse(os.Args[1:])
+//
+//	cfg.Load(
+//	    NewFileSource("config/app.yaml"),
+//	    NewEnvSource(WithEnvSourcePrefix("APP_")),
+//	    NewFlagSource(fs, mapping), // 只有命令行中出现的 flag 才会覆盖文件与环境变量
+//	)
+//
+// fs 为 nil 时使用 flag.CommandLine。Load 时读取 flag 的当前值，因此需要在 fs.Parse 之后调用 Load。
+func NewFlagSource(fs *flag.FlagSet, mapping map[string]string, opts ...FlagSourceOption) *FlagSource {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	f := &FlagSource{fs: fs, mapping: mapping, name: "flags"}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Name 返回该 Source 的名称。
+func (f *FlagSource) Name() string {
+	return f.name
+}
+
+// Priority 返回 PriorityFlags，可以用 Prioritized 包装来调整。
+func (f *FlagSource) Priority() int {
+	return PriorityFlags
+}
+
+// Load 实现 Source 接口。
+func (f *FlagSource) Load() ([]byte, Metadata, error) {
+	return f.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口，把显式设置的 flag 序列化为 JSON 交给 JSON Decoder 解析。
+func (f *FlagSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	out := make(map[string]any)
+	var err error
+	f.fs.Visit(func(fl *flag.Flag) {
+		path, ok := f.mapping[fl.Name]
+		if !ok && f.auto {
+			path, ok = strings.ReplaceAll(fl.Name, "-", "."), true
+		}
+		path = strings.Trim(path, ".")
+		if !ok || path == "" || err != nil {
+			return
+		}
+		v := flagValue(fl.Value)
+		if n, ok := v.(float64); ok && (math.IsInf(n, 0) || math.IsNaN(n)) {
+			// JSON 无法表示 Inf / NaN
+			err = fmt.Errorf("FlagSource: flag -%s: value %v is not a finite number", fl.Name, n)
+			return
+		}
+		insertNestedValue(out, strings.Split(path, "."), v)
+	})
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	return data, Metadata{Format: "json", Source: f.name}, nil
+}
+
+// flagValue 返回 flag 的值，保留 bool / 数字等类型；time.Duration 转换为 "1m30s" 形式以便 GetDuration 解析。
+func flagValue(v flag.Value) any {
+	g, ok := v.(flag.Getter)
+	if !ok {
+		return v.String()
+	}
+	switch t := g.Get().(type) {
+	case time.Duration:
+		return t.String()
+	case string, bool, int, int64, uint, uint64, float64:
+		return t
+	default:
+		return v.String()
+	}
+}
+
+// FlagKey 描述一个由 DefineFlagKeys 定义的 flag。
+type FlagKey struct {
+	// Path 是配置路径，例如 "db.host"
+	Path string
+	// Flag 是 flag 名称，为空时由 Path 推导（"." 替换为 "-"，例如 db-host）
+	Flag string
+	// Default 决定 flag 的类型与默认值，支持 string、bool、int、int64、uint、uint64、float64、time.Duration，
+	// 为 nil 时为 string 类型
+	Default any
+	// Usage 是 flag 的帮助信息
+	Usage string
+}
+
+// DefineFlagKeys 按 keys 在 fs 上定义 flag，返回供 NewFlagSource 使用的 flag 名称到配置路径的映射：
+//
+//	mapping, err := DefineFlagKeys(fs,
+//	    FlagKey{Path: "server.port", Default: 8080, Usage: "listen port"},
+//	    FlagKey{Path: "db.timeout", Default: 3 * time.Second},
+//	)
+//
+// flag 已存在或类型不受支持时返回错误。
+func DefineFlagKeys(fs *flag.FlagSet, keys ...FlagKey) (map[string]string, error) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	mapping := make(map[string]string, len(keys))
+	for _, k := range keys {
+		name := k.Flag
+		if name == "" {
+			name = flagName(k.Path)
+		}
+		if err := defineFlag(fs, name, k.Default, k.Usage); err != nil {
+			return nil, err
+		}
+		mapping[name] = k.Path
+	}
+	return mapping, nil
+}
+
+// DefineFlags 为结构体中的每个标量字段定义 flag，默认值取自 v 中字段的当前值，返回供 NewFlagSource 使用的映射。
+// 配置路径按 json 标签推导（没有 json 标签时为小写的字段名），与 Unmarshal 的映射规则一致：
+//
+//	type AppConfig struct {
+//	    Server struct {
+//	        Port int `json:"port" usage:"listen port"`
+//	    } `json:"server"`
+//	    DB struct {
+//	        Host    string        `json:"host" flag:"db"`  // 自定义 flag 名称 --db
+//	        Timeout time.Duration `json:"timeout"`
+//	        DSN     string        `json:"dsn" flag:"-"`    // 不定义 flag
+//	    } `json:"db"`
+//	}
+//
+//	mapping, err := DefineFlags(fs, &AppConfig{}) // --server-port、--db、--db-timeout
+//
+// 支持 string、bool、整数、浮点数与 time.Duration 字段，其他类型的字段被忽略。
+func DefineFlags(fs *flag.FlagSet, v any) (map[string]string, error) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			rv = reflect.New(rv.Type().Elem())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("DefineFlags: expected a struct, got %T", v)
+	}
+
+	var keys []FlagKey
+	collectFlagKeys(rv, "", &keys)
+	return DefineFlagKeys(fs, keys...)
+}
+
+// collectFlagKeys 收集结构体中可以定义为 flag 的字段。
+func collectFlagKeys(rv reflect.Value, prefix string, out *[]FlagKey) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		name, skip := jsonFieldName(f)
+		if skip || f.Tag.Get("flag") == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		for fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				fv = reflect.New(fv.Type().Elem())
+			}
+			fv = fv.Elem()
+		}
+		if f.Anonymous && name == "" {
+			// 匿名嵌入结构体的字段会被 encoding/json 提升到外层
+			if fv.Kind() == reflect.Struct {
+				collectFlagKeys(fv, prefix, out)
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		path := joinPath(prefix, name)
+
+		if fv.Kind() == reflect.Struct {
+			collectFlagKeys(fv, path, out)
+			continue
+		}
+		def, ok := flagDefault(fv)
+		if !ok {
+			continue
+		}
+		*out = append(*out, FlagKey{Path: path, Flag: f.Tag.Get("flag"), Default: def, Usage: f.Tag.Get("usage")})
+	}
+}
+
+// flagDefault 把字段值转换为 defineFlag 支持的默认值类型。
+func flagDefault(v reflect.Value) (any, bool) {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()), true
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return v.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return nil, false
+}
+
+// defineFlag 按默认值的类型在 fs 上定义 flag。
+func defineFlag(fs *flag.FlagSet, name string, def any, usage string) error {
+	if name == "" {
+		return fmt.Errorf("define flag: name is empty")
+	}
+	if fs.Lookup(name) != nil {
+		return fmt.Errorf("define flag %q: already defined", name)
+	}
+	switch d := def.(type) {
+	case nil:
+		fs.String(name, "", usage)
+	case string:
+		fs.String(name, d, usage)
+	case bool:
+		fs.Bool(name, d, usage)
+	case int:
+		fs.Int(name, d, usage)
+	case int64:
+		fs.Int64(name, d, usage)
+	case uint:
+		fs.Uint(name, d, usage)
+	case uint64:
+		fs.Uint64(name, d, usage)
+	case float64:
+		fs.Float64(name, d, usage)
+	case time.Duration:
+		fs.Duration(name, d, usage)
+	default:
+		return fmt.Errorf("define flag %q: unsupported default type %T", name, def)
+	}
+	return nil
+}
+
+// flagName 由配置路径推导 flag 名称，例如 db.host => db-host。
+func flagName(path string) string {
+	return strings.ReplaceAll(strings.Trim(path, "."), ".", "-")
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func newFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func TestFlagSource(t *testing.T) {
+	fs := newFlagSet()
+	fs.Int("port", 80, "")
+	fs.String("db-host", "localhost", "")
+	fs.Bool("debug", false, "")
+	fs.String("unmapped", "", "")
+	require.NoError(t, fs.Parse([]string{"--port=9090", "--db-host", "db.internal", "--unmapped=x"}))
+
+	t.Run("explicit mapping", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+		require.NoError(t, cfg.Load(
+			NewFlagSource(fs, map[string]string{"port": "server.port", "debug": "debug"}),
+			&switchSource{data: `{"server": {"port": 80}, "debug": true, "db": {"host": "file"}}`},
+		))
+
+		port, _ := cfg.GetInt("server.port")
+		debug, _ := cfg.GetBool("debug")
+		host, _ := cfg.GetString("db.host")
+		assert.Equal(t, 9090, port, "flags sit above files regardless of argument order")
+		assert.True(t, debug, "unset flags keep the file value")
+		assert.Equal(t, "file", host)
+
+		_, ok := cfg.Get("unmapped")
+		assert.False(t, ok)
+		assert.Equal(t, PriorityFlags, cfg.Layers()[1].Priority)
+		pos, _ := cfg.Position("server.port")
+		assert.Equal(t, "flags", pos.Source)
+	})
+
+	t.Run("auto mapping", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+		require.NoError(t, cfg.Load(NewFlagSource(fs, map[string]string{"port": "server.port"}, WithFlagSourceAutoMapping(), WithFlagSourceName("cli"))))
+
+		host, _ := cfg.GetString("db.host")
+		unmapped, _ := cfg.GetString("unmapped")
+		port, _ := cfg.GetInt("server.port")
+		assert.Equal(t, "db.internal", host)
+		assert.Equal(t, "x", unmapped)
+		assert.Equal(t, 9090, port)
+	})
+}
+
+func TestDefineFlags(t *testing.T) {
+	type appConfig struct {
+		Server struct {
+			Port int    `json:"port" usage:"listen port"`
+			Host string `json:"host"`
+		} `json:"server"`
+		DB struct {
+			Host    string        `json:"host" flag:"db"`
+			Timeout time.Duration `json:"timeout"`
+			DSN     string        `json:"dsn" flag:"-"`
+		} `json:"db"`
+		Verbose bool
+		Tags    []string `json:"tags"`
+	}
+
+	var defaults appConfig
+	defaults.Server.Port = 8080
+	fs := newFlagSet()
+	mapping, err := DefineFlags(fs, &defaults)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"server-port": "server.port",
+		"server-host": "server.host",
+		"db":          "db.host",
+		"db-timeout":  "db.timeout",
+		"verbose":     "verbose",
+	}, mapping)
+	assert.Equal(t, "8080", fs.Lookup("server-port").DefValue)
+	assert.Equal(t, "listen port", fs.Lookup("server-port").Usage)
+
+	require.NoError(t, fs.Parse([]string{"--db=db.internal", "--db-timeout=1m30s", "--verbose"}))
+	cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+	require.NoError(t, cfg.Load(&switchSource{data: `{"server": {"port": 80}}`}, NewFlagSource(fs, mapping)))
+
+	port, _ := cfg.GetInt("server.port")
+	host, _ := cfg.GetString("db.host")
+	verbose, _ := cfg.GetBool("verbose")
+	assert.Equal(t, 80, port, "defaults of unset flags do not override files")
+	assert.Equal(t, "db.internal", host)
+	assert.True(t, verbose)
+	timeout, _ := cfg.GetDuration("db.timeout")
+	assert.Equal(t, 90*time.Second, timeout)
+
+	// 定义 flag 所用的结构体可以直接接收结果，time.Duration 字段接受 "1m30s"
+	var got appConfig
+	require.NoError(t, cfg.Unmarshal(&got))
+	assert.Equal(t, 80, got.Server.Port)
+	assert.Equal(t, "db.internal", got.DB.Host)
+	assert.Equal(t, 90*time.Second, got.DB.Timeout)
+	assert.True(t, got.Verbose)
+
+	_, err = DefineFlags(fs, &defaults)
+	assert.ErrorContains(t, err, "already defined")
+
+	ratio := newFlagSet()
+	ratio.Float64("ratio", 0.5, "")
+	require.NoError(t, ratio.Parse([]string{"--ratio=+Inf"}))
+	err = cfg.Load(NewFlagSource(ratio, map[string]string{"ratio": "sampling.ratio"}))
+	assert.ErrorContains(t, err, "flag -ratio: value +Inf is not a finite number")
+
+	mapping, err = DefineFlagKeys(newFlagSet(), FlagKey{Path: "log.level", Default: "info"}, FlagKey{Path: "workers", Flag: "n", Default: 4})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"log-level": "log.level", "n": "workers"}, mapping)
+	_, err = DefineFlagKeys(newFlagSet(), FlagKey{Path: "x", Default: []string{}})
+	assert.Error(t, err)
+}
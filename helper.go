@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -76,14 +78,17 @@ func flattenKeys(prefix string, v any, out *[]string) {
 	}
 }
 
-// expandMapInPlace 在整个 map 结构中递归替换 string 值里的占位符
-func expandMapInPlace(m map[string]any, exp VariableExpander, lookup func(string) (string, bool)) error {
+// expandMapInPlace 在整个 map 结构中递归替换 string 值里的占位符。
+// prefix 为 m 所在的路径；sensitive 用于判断某路径是否敏感，
+// 敏感路径展开失败时，错误信息中不会包含原始值。
+func expandMapInPlace(m map[string]any, prefix string, exp VariableExpander, lookup func(string) (string, bool), sensitive func(string) bool) error {
 	for k, v := range m {
+		path := joinPath(prefix, k)
 		switch vv := v.(type) {
 		case string:
 			out, err := exp.Expand(vv, lookup)
 			if err != nil {
-				return err
+				return expandError(path, err, sensitive)
 			}
 			m[k] = out
 		case map[string]any, map[any]any:
@@ -91,7 +96,7 @@ func expandMapInPlace(m map[string]any, exp VariableExpander, lookup func(string
 			if !ok {
 				continue
 			}
-			if err := expandMapInPlace(sub, exp, lookup); err != nil {
+			if err := expandMapInPlace(sub, path, exp, lookup, sensitive); err != nil {
 				return err
 			}
 			m[k] = sub
@@ -101,7 +106,7 @@ func expandMapInPlace(m map[string]any, exp VariableExpander, lookup func(string
 				case string:
 					out, err := exp.Expand(iv, lookup)
 					if err != nil {
-						return err
+						return expandError(path, err, sensitive)
 					}
 					vv[i] = out
 				case map[string]any, map[any]any:
@@ -109,7 +114,7 @@ func expandMapInPlace(m map[string]any, exp VariableExpander, lookup func(string
 					if !ok {
 						continue
 					}
-					if err := expandMapInPlace(sub, exp, lookup); err != nil {
+					if err := expandMapInPlace(sub, path, exp, lookup, sensitive); err != nil {
 						return err
 					}
 					vv[i] = sub
@@ -125,10 +130,61 @@ func expandMapInPlace(m map[string]any, exp VariableExpander, lookup func(string
 	return nil
 }
 
+// expandError 为占位符展开错误附加路径信息。
+// 展开错误通常会引用原始字符串，因此敏感路径只保留路径，丢弃底层错误文本。
+func expandError(path string, err error, sensitive func(string) bool) error {
+	if sensitive != nil && sensitive(path) {
+		return fmt.Errorf("expand value of %q failed: %s", path, RedactedValue)
+	}
+	return fmt.Errorf("expand value of %q failed: %w", path, err)
+}
+
+// joinPath 拼接 "a.b" 形式的路径。
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// matchPathPattern 判断 "a.b.c" 形式的路径是否匹配 pattern。
+// pattern 同样以 "." 分段，每段支持 path.Match 语法：
+//   - "*"  匹配任意单个层级，例如 "*.password" 匹配 "db.password"
+//   - "**" 匹配零个或多个层级，例如 "**.token" 匹配 "token"、"a.b.token"
+func matchPathPattern(pattern, p string) bool {
+	if pattern == "" {
+		return false
+	}
+	return matchSegments(strings.Split(pattern, "."), strings.Split(p, "."))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], parts[0]); err != nil || !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
 // detectFormatFromPath 根据文件路径的扩展名推断配置格式。
 //   - .json -> "json"
 //   - .yaml/.yml -> "yaml"
 //   - .toml -> "toml"
+//   - .properties -> "properties"
 //
 // 如果扩展名未知或没有扩展名，会返回错误，提醒调用方显式配置格式。
 func detectFormatFromPath(path string) (string, error) {
@@ -144,6 +200,8 @@ func detectFormatFromPath(path string) (string, error) {
 		return "yaml", nil
 	case ".toml":
 		return "toml", nil
+	case ".properties":
+		return "properties", nil
 	default:
 		return "", fmt.Errorf("FileSource: unsupported file extension %q in path %q", ext, path)
 	}
@@ -379,6 +437,13 @@ func expandAllPlaceholders(input string, lookup func(string) (string, bool)) (st
 		// 提取占位符内部内容
 		rawExpr := input[begin : begin+end]
 
+		// ${enc:...} 是加密值，原样保留，交给 Decryptor 处理
+		if strings.HasPrefix(rawExpr, encPlaceholderPrefix) {
+			res.WriteString(input[start+idx : begin+end+1])
+			start = begin + end + 1
+			continue
+		}
+
 		// 替换占位符
 		expanded, err := expandSinglePlaceholder(rawExpr, lookup)
 		if err != nil {
@@ -481,3 +546,150 @@ func detectFormatFromContentType(ct string) (string, bool) {
 		return "", false
 	}
 }
+
+// cloneMap 深拷贝 map[string]any，嵌套的 map 与 slice 均会复制，标量值直接共享。
+func cloneMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = cloneValue(v)
+	}
+	return out
+}
+
+func cloneValue(v any) any {
+	if sub, ok := toStringMap(v); ok {
+		return cloneMap(sub)
+	}
+	if list, ok := v.([]any); ok {
+		cp := make([]any, len(list))
+		for i, item := range list {
+			cp[i] = cloneValue(item)
+		}
+		return cp
+	}
+	return v
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// hasDuration 报告类型 t 中是否包含 time.Duration（含嵌套的结构体、map、slice 与指针）。
+func hasDuration(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == durationType {
+		return true
+	}
+	if seen[t] {
+		return false
+	}
+	if seen == nil {
+		seen = make(map[reflect.Type]bool)
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasDuration(t.Field(i).Type, seen) {
+				return true
+			}
+		}
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return hasDuration(t.Elem(), seen)
+	}
+	return false
+}
+
+// durationsToNanos 返回 v 的副本，其中与 t 中 time.Duration 对应的字符串（"1m30s"）被转换为纳秒数，
+// 使 JSON 中转后能够解码到 time.Duration 字段。无法解析的字符串保持原样，由 json.Unmarshal 报告错误。
+func durationsToNanos(v any, t reflect.Type) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == durationType {
+		if s, ok := v.(string); ok {
+			if d, err := time.ParseDuration(s); err == nil {
+				return int64(d)
+			}
+		}
+		return v
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := toStringMap(v)
+		if !ok {
+			return v
+		}
+		out := make(map[string]any, len(m))
+		for k, val := range m {
+			if ft, ok := jsonFieldType(t, k); ok {
+				val = durationsToNanos(val, ft)
+			}
+			out[k] = val
+		}
+		return out
+	case reflect.Map:
+		m, ok := toStringMap(v)
+		if !ok {
+			return v
+		}
+		out := make(map[string]any, len(m))
+		for k, val := range m {
+			out[k] = durationsToNanos(val, t.Elem())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		list, ok := v.([]any)
+		if !ok {
+			return v
+		}
+		out := make([]any, len(list))
+		for i, val := range list {
+			out[i] = durationsToNanos(val, t.Elem())
+		}
+		return out
+	}
+	return v
+}
+
+// jsonFieldType 按 encoding/json 的规则（json 标签优先、大小写不敏感、展开匿名嵌入结构体）
+// 返回结构体 t 中与 key 对应的字段类型。
+func jsonFieldType(t reflect.Type, key string) (reflect.Type, bool) {
+	var fold reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, skip := jsonFieldName(f)
+		if skip {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if typ, ok := jsonFieldType(ft, key); ok {
+					return typ, true
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if name == key {
+			return f.Type, true
+		}
+		if fold == nil && strings.EqualFold(name, key) {
+			fold = f.Type
+		}
+	}
+	return fold, fold != nil
+}
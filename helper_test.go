@@ -0,0 +1,15 @@
+package config
+
+import "github.com/lifei6671/go-config/decoder"
+
+// newFileConfig 返回注册了 yaml/json/toml 解码器与 yaml/json 编码器的配置，
+// 供读写本地文件的测试共用；opts 追加在默认选项之后。
+func newFileConfig(opts ...Option) *DefaultConfig {
+	return NewDefaultConfig(append([]Option{
+		WithDecoder(decoder.YAMLDecoder{}),
+		WithDecoder(decoder.JSONDecoder{}),
+		WithDecoder(decoder.TOMLDecoder{}),
+		WithEncoder(decoder.YAMLEncoder{}),
+		WithEncoder(decoder.JSONEncoder{}),
+	}, opts...)...)
+}
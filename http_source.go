@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -127,8 +128,19 @@ func NewHTTPSource(urlStr string, opts ...HTTPSourceOption) *HTTPSource {
 	return hs
 }
 
+// Name 返回该 Source 的逻辑名称，与 Metadata.Source 一致。
+func (hs *HTTPSource) Name() string {
+	return hs.name
+}
+
 // Load 实现 Source 接口：发起 HTTP 请求，返回 body + 元数据。
 func (hs *HTTPSource) Load() ([]byte, Metadata, error) {
+	return hs.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口，ctx 绑定到 HTTP 请求上。
+// client 自身的 Timeout 仍然生效，两者以先到者为准。
+func (hs *HTTPSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
 	if hs.url == "" {
 		return nil, Metadata{}, fmt.Errorf("HTTPSource: url is empty")
 	}
@@ -136,7 +148,7 @@ func (hs *HTTPSource) Load() ([]byte, Metadata, error) {
 		return nil, Metadata{}, fmt.Errorf("HTTPSource: invalid url %q: %w", hs.url, err)
 	}
 
-	req, err := http.NewRequest(hs.method, hs.url, nil)
+	req, err := http.NewRequestWithContext(ctx, hs.method, hs.url, nil)
 	if err != nil {
 		return nil, Metadata{}, fmt.Errorf("HTTPSource: new request failed: %w", err)
 	}
@@ -151,7 +163,7 @@ func (hs *HTTPSource) Load() ([]byte, Metadata, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, Metadata{}, fmt.Errorf("HTTPSource: non-2xx status code: %d", resp.StatusCode)
+		return nil, Metadata{}, fmt.Errorf("HTTPSource: non-2xx status code: %w", &HTTPStatusError{URL: hs.url, StatusCode: resp.StatusCode})
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -178,8 +190,9 @@ func (hs *HTTPSource) Load() ([]byte, Metadata, error) {
 	}
 
 	meta := Metadata{
-		Format: format,
-		Source: hs.name,
+		Format:   format,
+		Source:   hs.name,
+		Revision: resp.Header.Get("ETag"),
 	}
 	return body, meta, nil
 }
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// 声明被包含文件的保留 key，只在文件顶层生效。
+const (
+	// IncludeKey 是包含其他文件的 key，例如 $include: [db.yaml, "conf.d/*.yaml"]
+	IncludeKey = "$include"
+	// ImportsKey 与 IncludeKey 等价，便于不方便使用 $ 的场景
+	ImportsKey = "imports"
+)
+
+// WithIncludes 允许 FileSource 通过顶层的 $include 或 imports 引用其他文件，YAML / JSON / TOML 写法一致：
+//
+//	# config/app.yaml
+//	$include:
+//	  - db.yaml                              # 相对于当前文件所在目录
+//	  - conf.d/*.yaml                        # glob，没有匹配的文件时忽略，按字典序合并
+//	  - {path: local.yaml, optional: true}   # 文件不存在时跳过
+//	server:
+//	  port: 8080                             # 当前文件的内容覆盖被包含的内容
+//
+// 被包含的文件从同一个文件系统读取（WithFileSourceFS 指定的 embed.FS、os.DirFS 等），格式按扩展名推断，
+// 可以继续包含其他文件；出现循环包含时返回 *IncludeCycleError。多个被包含文件按声明顺序合并，
+// 当前文件的内容最后合并。Position() 指向值实际所在的文件。
+//
+// 未开启时 $include 与 imports 按普通 key 处理。
+func WithIncludes() Option {
+	return func(c *DefaultConfig) {
+		c.includes = true
+	}
+}
+
+// IncludeCycleError 表示文件之间存在循环包含。
+type IncludeCycleError struct {
+	// Chain 是从顶层文件开始的包含链，最后一项与链中某一项相同
+	Chain []string
+}
+
+func (e *IncludeCycleError) Error() string {
+	return "include cycle: " + strings.Join(e.Chain, " -> ")
+}
+
+// includeRef 是 $include 中声明的一项。
+type includeRef struct {
+	path     string
+	optional bool
+}
+
+// takeIncludes 从 data 顶层取出并删除 $include / imports 声明。
+func takeIncludes(data map[string]any) ([]includeRef, error) {
+	var refs []includeRef
+	for _, key := range []string{IncludeKey, ImportsKey} {
+		v, ok := data[key]
+		if !ok {
+			continue
+		}
+		delete(data, key)
+
+		items, ok := v.([]any)
+		if !ok {
+			items = []any{v}
+		}
+		for _, item := range items {
+			ref, err := parseIncludeRef(key, item)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+// parseIncludeRef 解析单个包含项，支持字符串或 {path, optional} 形式。
+func parseIncludeRef(key string, v any) (includeRef, error) {
+	if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+		return includeRef{path: strings.TrimSpace(s)}, nil
+	}
+	m, ok := toStringMap(v)
+	if !ok {
+		return includeRef{}, fmt.Errorf("%s: entry must be a string or a map with path, got %T", key, v)
+	}
+	p, _ := m["path"].(string)
+	if strings.TrimSpace(p) == "" {
+		return includeRef{}, fmt.Errorf("%s: entry is missing path", key)
+	}
+	optional, _ := m["optional"].(bool)
+	return includeRef{path: strings.TrimSpace(p), optional: optional}, nil
+}
+
+// resolveInclude 返回 ref 相对于 from 所在目录解析后的文件列表，glob 按字典序展开。
+func (f *FileSource) resolveInclude(from string, ref includeRef) ([]string, error) {
+	var p string
+	if f.fsys != nil {
+		p = path.Join(path.Dir(from), ref.path)
+	} else if filepath.IsAbs(ref.path) {
+		p = filepath.Clean(ref.path)
+	} else {
+		p = filepath.Join(filepath.Dir(from), ref.path)
+	}
+
+	if !strings.ContainsAny(ref.path, "*?[") {
+		return []string{p}, nil
+	}
+	if f.fsys != nil {
+		return fs.Glob(f.fsys, p)
+	}
+	return filepath.Glob(p)
+}
+
+// cleanFilePath 规范化 FileSource 使用的路径，用于循环检测。
+func (f *FileSource) cleanFilePath(p string) string {
+	if f.fsys != nil {
+		return path.Clean(p)
+	}
+	return filepath.Clean(p)
+}
+
+// includeBase 返回解析 src 中 $include 所用的 FileSource：普通文件层沿装饰器链查找，
+// Kubernetes 卷中的文件层（不暴露为 FileSource）使用挂载目录中的路径。
+func includeBase(src Source) (*FileSource, bool) {
+	if k, ok := findSource[interface{ includeSource() *FileSource }](src); ok {
+		return k.includeSource(), true
+	}
+	return findSource[*FileSource](src)
+}
+
+// resolveIncludes 展开 FileSource 层中的 $include，返回被包含内容与当前文件合并后的层。
+func (c *DefaultConfig) resolveIncludes(f *FileSource, layer *decodedLayer) (*decodedLayer, error) {
+	top := f.cleanFilePath(f.path)
+	return c.includeFiles(f, top, layer, []string{top})
+}
+
+func (c *DefaultConfig) includeFiles(f *FileSource, file string, layer *decodedLayer, chain []string) (*decodedLayer, error) {
+	refs, err := takeIncludes(layer.data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if len(refs) == 0 {
+		return layer, nil
+	}
+
+	base := make(map[string]any)
+	origins := make(map[string]Position)
+	var secrets []string
+	for _, ref := range refs {
+		files, err := f.resolveInclude(file, ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s: resolve include %q failed: %w", file, ref.path, err)
+		}
+		for _, p := range files {
+			for _, seen := range chain {
+				if seen == p {
+					return nil, &IncludeCycleError{Chain: append(append([]string(nil), chain...), p)}
+				}
+			}
+
+			raw, err := f.readFile(p)
+			if err != nil {
+				if ref.optional && errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				return nil, fmt.Errorf("%s: include failed: %w", file, err)
+			}
+			format, err := detectFormatFromPath(p)
+			if err != nil {
+				return nil, fmt.Errorf("%s: include failed: %w", file, err)
+			}
+			inc, err := c.decode(raw, format, p)
+			if err != nil {
+				return nil, err
+			}
+			inc, err = c.includeFiles(f, p, inc, append(chain[:len(chain):len(chain)], p))
+			if err != nil {
+				return nil, err
+			}
+
+			recordPositions(origins, "", inc.data, p, inc.positions)
+			for k, v := range inc.origins {
+				if pos, ok := origins[k]; ok && pos.Source == p {
+					origins[k] = v
+				}
+			}
+			if base, err = c.merge.Merge(base, inc.data); err != nil {
+				return nil, fmt.Errorf("%s: merge include %q failed: %w", file, p, err)
+			}
+			secrets = append(secrets, inc.secrets...)
+		}
+	}
+
+	merged, err := c.merge.Merge(base, layer.data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: merge includes failed: %w", file, err)
+	}
+	// 当前文件自己设置的路径以当前文件为准
+	var own []string
+	collectPaths("", layer.data, &own)
+	for _, p := range own {
+		delete(origins, p)
+	}
+
+	layer.data = merged
+	layer.origins = origins
+	layer.secrets = append(secrets, layer.secrets...)
+	return layer, nil
+}
+
+// collectPaths 收集 m 中所有节点（含中间节点）的路径。
+func collectPaths(prefix string, m map[string]any, out *[]string) {
+	for k, v := range m {
+		p := joinPath(prefix, k)
+		*out = append(*out, p)
+		if sub, ok := toStringMap(v); ok {
+			collectPaths(p, sub, out)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestIncludes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/app.yaml": {Data: []byte(`$include:
+  - db.json
+  - conf.d/*.toml
+  - {path: local.yaml, optional: true}
+server:
+  port: 8080
+`)},
+		"config/db.json":            {Data: []byte(`{"db": {"host": "db.internal", "port": 5432}, "server": {"port": 80, "host": "0.0.0.0"}}`)},
+		"config/conf.d/10-log.toml": {Data: []byte("imports = [\"../shared/log.yaml\"]\n[log]\nlevel = \"info\"\n")},
+		"config/conf.d/20-db.toml":  {Data: []byte("[db]\nport = 6432\n")},
+		"config/shared/log.yaml":    {Data: []byte("log:\n  level: debug\n  format: json\n")},
+	}
+
+	cfg := newFileConfig(WithIncludes())
+	require.NoError(t, cfg.Load(NewFileSource("config/app.yaml", WithFileSourceFS(fsys))))
+
+	var got map[string]any
+	require.NoError(t, cfg.Unmarshal(&got))
+	assert.Equal(t, map[string]any{
+		"server": map[string]any{"port": float64(8080), "host": "0.0.0.0"},
+		"db":     map[string]any{"host": "db.internal", "port": float64(6432)},
+		"log":    map[string]any{"level": "info", "format": "json"},
+	}, got)
+
+	for path, source := range map[string]string{
+		"server.port": "config/app.yaml",
+		"server.host": "config/db.json",
+		"db.host":     "config/db.json",
+		"db.port":     "config/conf.d/20-db.toml",
+		"log.level":   "config/conf.d/10-log.toml",
+		"log.format":  "config/shared/log.yaml",
+	} {
+		pos, ok := cfg.Position(path)
+		require.True(t, ok, path)
+		assert.Equal(t, source, pos.Source, path)
+	}
+	pos, _ := cfg.Position("log.format")
+	assert.Equal(t, 3, pos.Line)
+}
+
+func TestIncludes_Errors(t *testing.T) {
+	t.Run("cycle", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.yaml": {Data: []byte("$include: b.yaml\na: 1\n")},
+			"b.yaml": {Data: []byte("$include: [a.yaml]\nb: 1\n")},
+		}
+		err := newFileConfig(WithIncludes()).Load(NewFileSource("a.yaml", WithFileSourceFS(fsys)))
+		var cycle *IncludeCycleError
+		require.ErrorAs(t, err, &cycle)
+		assert.Equal(t, []string{"a.yaml", "b.yaml", "a.yaml"}, cycle.Chain)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		fsys := fstest.MapFS{"a.yaml": {Data: []byte("$include: missing.yaml\n")}}
+		err := newFileConfig(WithIncludes()).Load(NewFileSource("a.yaml", WithFileSourceFS(fsys)))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		fsys := fstest.MapFS{"a.yaml": {Data: []byte("imports: [x]\n")}}
+		cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}))
+		require.NoError(t, cfg.Load(NewFileSource("a.yaml", WithFileSourceFS(fsys))))
+		_, ok := cfg.Get("imports")
+		assert.True(t, ok)
+	})
+}
+
+func TestIncludes_OSFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "base.yaml"), []byte("name: base\nport: 80\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("$include: base.yaml\nport: 8080\n"), 0o600))
+
+	cfg := newFileConfig(WithIncludes())
+	require.NoError(t, cfg.Load(NewFileSource(filepath.Join(dir, "app.yaml"))))
+	name, _ := cfg.GetString("name")
+	port, _ := cfg.GetInt("port")
+	assert.Equal(t, "base", name)
+	assert.Equal(t, 8080, port)
+	pos, _ := cfg.Position("name")
+	assert.Equal(t, filepath.Join(dir, "base.yaml"), pos.Source)
+}
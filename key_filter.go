@@ -0,0 +1,340 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// KeyFilter 限制一个 Source 可以设置的配置路径。路径模式支持 * 与 ** 通配符，
+// 模式匹配某个路径或其任一祖先时即视为匹配，例如 "log" 与 "log.**" 等价。
+type KeyFilter struct {
+	// Allow 非空时，只有匹配其中任一模式的路径可以被设置
+	Allow []string
+	// Deny 中匹配的路径总是被拒绝，优先于 Allow
+	Deny []string
+}
+
+// check 返回 path 被拒绝的原因，允许时返回空字符串。
+func (f KeyFilter) check(path string) string {
+	if p, ok := matchPathOrAncestor(f.Deny, path); ok {
+		return fmt.Sprintf("denied by %q", p)
+	}
+	if len(f.Allow) > 0 {
+		if _, ok := matchPathOrAncestor(f.Allow, path); !ok {
+			return "not in allowlist"
+		}
+	}
+	return ""
+}
+
+// FilteredSource 是 WithKeyFilter 返回的装饰器。
+type FilteredSource struct {
+	src    Source
+	filter KeyFilter
+}
+
+var _ ContextSource = (*FilteredSource)(nil)
+
+// WithKeyFilter 限制 src 可以设置的路径，不符合的 key 在合并前被丢弃，并记录在 LoadReport.RejectedKeys 中：
+//
+//	// env 层只能修改日志配置与端口
+//	WithKeyFilter(NewEnvSource(), KeyFilter{Allow: []string{"log.*", "server.port"}})
+func WithKeyFilter(src Source, filter KeyFilter) *FilteredSource {
+	return &FilteredSource{src: src, filter: filter}
+}
+
+// KeyFilter 返回该 Source 的路径过滤规则。
+func (f *FilteredSource) KeyFilter() KeyFilter {
+	return f.filter
+}
+
+// Name 返回被包装 Source 的名称。
+func (f *FilteredSource) Name() string {
+	return sourceName(f.src, Metadata{})
+}
+
+// Unwrap 返回被包装的原始 Source。
+func (f *FilteredSource) Unwrap() Source {
+	return f.src
+}
+
+// Load 实现 Source 接口。
+func (f *FilteredSource) Load() ([]byte, Metadata, error) {
+	return f.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (f *FilteredSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	return loadSource(ctx, f.src)
+}
+
+// TrustLevel 是 Source 的可信级别，配合 RequireTrust 限制低可信 Source 能修改的路径。
+type TrustLevel int
+
+const (
+	TrustUntrusted TrustLevel = 0
+	TrustLow       TrustLevel = 10
+	TrustMedium    TrustLevel = 20
+	// TrustHigh 是未通过 WithTrust 标记的 Source 的默认级别
+	TrustHigh TrustLevel = 30
+)
+
+func (l TrustLevel) String() string {
+	switch l {
+	case TrustUntrusted:
+		return "untrusted"
+	case TrustLow:
+		return "low"
+	case TrustMedium:
+		return "medium"
+	case TrustHigh:
+		return "high"
+	}
+	return fmt.Sprintf("TrustLevel(%d)", int(l))
+}
+
+// TrustedSource 是 WithTrust 返回的装饰器。
+type TrustedSource struct {
+	src   Source
+	level TrustLevel
+}
+
+var _ ContextSource = (*TrustedSource)(nil)
+
+// WithTrust 为 src 指定可信级别。级别低于 RequireTrust 要求的 Source 不能设置对应路径：
+//
+//	cfg.RequireTrust(TrustHigh, "auth.**", "server.tls.**")
+//	cfg.Load(
+//	    NewFileSource("config/app.yaml"),                          // 默认 TrustHigh
+//	    WithTrust(NewHTTPSource("http://cc/app.json"), TrustLow), // 不能修改 auth 与 tls
+//	)
+func WithTrust(src Source, level TrustLevel) *TrustedSource {
+	return &TrustedSource{src: src, level: level}
+}
+
+// TrustLevel 返回该 Source 的可信级别。
+func (t *TrustedSource) TrustLevel() TrustLevel {
+	return t.level
+}
+
+// Name 返回被包装 Source 的名称。
+func (t *TrustedSource) Name() string {
+	return sourceName(t.src, Metadata{})
+}
+
+// Unwrap 返回被包装的原始 Source。
+func (t *TrustedSource) Unwrap() Source {
+	return t.src
+}
+
+// Load 实现 Source 接口。
+func (t *TrustedSource) Load() ([]byte, Metadata, error) {
+	return t.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (t *TrustedSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	return loadSource(ctx, t.src)
+}
+
+// trustRequirement 是 RequireTrust 注册的一条规则。
+type trustRequirement struct {
+	level    TrustLevel
+	patterns []string
+}
+
+// RequireTrust 要求只有可信级别不低于 level 的 Source 才能设置匹配 patterns 的路径，
+// 低级别 Source 中的这些 key 在合并前被丢弃并记录在 LoadReport.RejectedKeys 中。在下一次 Load 时生效。
+func (c *DefaultConfig) RequireTrust(level TrustLevel, patterns ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.trust = append(c.trust, trustRequirement{level: level, patterns: patterns})
+}
+
+// RejectedKey 描述一个因 KeyFilter 或可信级别被丢弃的 key。
+type RejectedKey struct {
+	Path   string
+	Source string
+	Reason string
+}
+
+// keyGuard 汇总一个 Source 的路径过滤规则与可信级别要求。
+type keyGuard struct {
+	keys         KeyFilter
+	hasFilter    bool
+	level        TrustLevel
+	requirements []trustRequirement
+	// existing 是该层合并之前的配置，用于检查覆盖或删除祖先节点时是否会抹掉受保护的子路径
+	existing map[string]any
+}
+
+// newKeyGuard 返回 src 在 existing 之上写入时的路径检查规则，没有任何限制时返回 nil。
+func newKeyGuard(src Source, requirements []trustRequirement, existing map[string]any) *keyGuard {
+	g := &keyGuard{level: TrustHigh, existing: existing}
+	if f, ok := findSource[interface{ KeyFilter() KeyFilter }](src); ok {
+		g.keys, g.hasFilter = f.KeyFilter(), true
+	}
+	if t, ok := findSource[interface{ TrustLevel() TrustLevel }](src); ok {
+		g.level = t.TrustLevel()
+	}
+	for _, r := range requirements {
+		if g.level < r.level {
+			g.requirements = append(g.requirements, r)
+		}
+	}
+	if !g.hasFilter && len(g.requirements) == 0 {
+		return nil
+	}
+	return g
+}
+
+// check 返回设置 path 被拒绝的原因，允许时返回空字符串。
+func (g *keyGuard) check(path string) string {
+	if g.hasFilter {
+		if reason := g.keys.check(path); reason != "" {
+			return reason
+		}
+	}
+	for _, r := range g.requirements {
+		if p, ok := matchPathOrAncestor(r.patterns, path); ok {
+			return fmt.Sprintf("trust level %s below %s required by %q", g.level, r.level, p)
+		}
+	}
+	return ""
+}
+
+// checkOverwrite 与 check 相同，但用于整体替换或删除 path 的写入（标量覆盖 map、$replace、$delete、
+// JSON Patch 的 replace / remove 等）：path 下已有的子路径中只要有一个被 Deny 或可信级别要求保护，写入即被拒绝。
+func (g *keyGuard) checkOverwrite(path string) string {
+	if reason := g.check(path); reason != "" {
+		return reason
+	}
+	v := any(g.existing)
+	if path != "" {
+		v, _ = getByPath(g.existing, path)
+	}
+	if _, ok := toStringMap(v); !ok {
+		return ""
+	}
+	var children []string
+	flattenKeys(path, v, &children)
+	sort.Strings(children)
+	for _, child := range children {
+		if reason := g.protected(child); reason != "" {
+			return fmt.Sprintf("%s (would overwrite %q)", reason, child)
+		}
+	}
+	return ""
+}
+
+// protected 返回 path 被 Deny 或可信级别要求保护的原因。Allow 不参与判断：
+// 写入的路径已通过 Allow 检查时，其子路径同样被允许。
+func (g *keyGuard) protected(path string) string {
+	if g.hasFilter {
+		if p, ok := matchPathOrAncestor(g.keys.Deny, path); ok {
+			return fmt.Sprintf("denied by %q", p)
+		}
+	}
+	for _, r := range g.requirements {
+		if p, ok := matchPathOrAncestor(r.patterns, path); ok {
+			return fmt.Sprintf("trust level %s below %s required by %q", g.level, r.level, p)
+		}
+	}
+	return ""
+}
+
+// filter 返回去掉被拒绝路径之后的数据，以及被拒绝的路径与原因。
+// directives 为 true 时按合并指令的语义检查：$delete 中的每个 key 与 $replace / $append 所在路径都需要被允许。
+func (g *keyGuard) filter(prefix string, m map[string]any, directives bool) (map[string]any, []RejectedKey) {
+	out := make(map[string]any, len(m))
+	var rejected []RejectedKey
+	reject := func(path, reason string) {
+		rejected = append(rejected, RejectedKey{Path: path, Reason: reason})
+	}
+
+	for k, v := range m {
+		path := joinPath(prefix, k)
+		if directives && isDirectiveKey(k) {
+			switch k {
+			case DirectiveDelete:
+				keys, err := directiveKeys(prefix, v)
+				if err != nil {
+					// 格式错误留给合并阶段报告
+					out[k] = v
+					continue
+				}
+				var kept []any
+				for _, dk := range keys {
+					if reason := g.checkOverwrite(joinPath(prefix, dk)); reason != "" {
+						reject(joinPath(prefix, dk), reason)
+						continue
+					}
+					kept = append(kept, dk)
+				}
+				if len(kept) > 0 {
+					out[k] = kept
+				}
+			default:
+				// $replace / $append 作用于所在的路径本身，并替换其下已有的子路径
+				if reason := g.checkOverwrite(prefix); reason != "" {
+					reject(prefix, reason)
+					continue
+				}
+				out[k] = v
+			}
+			continue
+		}
+
+		if sub, ok := toStringMap(v); ok && len(sub) > 0 {
+			if directives {
+				if _, ok := sub[DirectiveAppend]; ok {
+					if reason := g.checkOverwrite(path); reason != "" {
+						reject(path, reason)
+						continue
+					}
+					out[k] = v
+					continue
+				}
+			}
+			filtered, r := g.filter(path, sub, directives)
+			rejected = append(rejected, r...)
+			if len(filtered) > 0 {
+				out[k] = filtered
+			}
+			continue
+		}
+
+		// 空 map 深度合并时不会改变已有子树，其他值会整体覆盖 path 下已有的子路径
+		check := g.checkOverwrite
+		if _, ok := toStringMap(v); ok {
+			check = g.check
+		}
+		if reason := check(path); reason != "" {
+			reject(path, reason)
+			continue
+		}
+		out[k] = v
+	}
+
+	sort.Slice(rejected, func(i, j int) bool { return rejected[i].Path < rejected[j].Path })
+	return out, rejected
+}
+
+// matchPathOrAncestor 返回匹配 path 或其任一祖先路径的第一个模式。
+func matchPathOrAncestor(patterns []string, path string) (string, bool) {
+	for _, p := range patterns {
+		for cur := path; ; {
+			if matchPathPattern(p, cur) {
+				return p, true
+			}
+			i := strings.LastIndexByte(cur, '.')
+			if i < 0 {
+				break
+			}
+			cur = cur[:i]
+		}
+	}
+	return "", false
+}
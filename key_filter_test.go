@@ -0,0 +1,119 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestKeyFilter(t *testing.T) {
+	base := Prioritized("base", PriorityFiles, &switchSource{data: `{"log":{"level":"info"},"server":{"port":80,"host":"0.0.0.0"},"auth":{"enabled":true}}`})
+	env := fakeEnvSource("env", "LOG__LEVEL=debug", "LOG__FORMAT=json", "SERVER__PORT=8080", "SERVER__HOST=evil", "AUTH__ENABLED=false")
+
+	cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}), WithDecoder(decoder.YAMLDecoder{}))
+	require.NoError(t, cfg.Load(base, WithKeyFilter(env, KeyFilter{
+		Allow: []string{"log.*", "server.port"},
+		Deny:  []string{"log.format"},
+	})))
+
+	level, _ := cfg.GetString("log.level")
+	assert.Equal(t, "debug", level)
+	port, _ := cfg.GetInt("server.port")
+	assert.Equal(t, 8080, port)
+	host, _ := cfg.GetString("server.host")
+	assert.Equal(t, "0.0.0.0", host)
+	enabled, _ := cfg.GetBool("auth.enabled")
+	assert.True(t, enabled)
+	_, ok := cfg.Get("log.format")
+	assert.False(t, ok)
+
+	assert.Equal(t, []RejectedKey{
+		{Path: "auth.enabled", Source: "env", Reason: "not in allowlist"},
+		{Path: "log.format", Source: "env", Reason: `denied by "log.format"`},
+		{Path: "server.host", Source: "env", Reason: "not in allowlist"},
+	}, cfg.LoadReport().RejectedKeys)
+}
+
+func TestRequireTrust(t *testing.T) {
+	base := Prioritized("base", PriorityFiles, &switchSource{data: `{"auth":{"enabled":true,"users":["admin"]},"feature":false}`})
+	remote := Prioritized("remote", PriorityRemote, &switchSource{data: `{
+		"auth": {"$replace": true, "enabled": false},
+		"$delete": ["feature"]
+	}`})
+
+	cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}), WithMergeDirectives())
+	cfg.RequireTrust(TrustHigh, "auth", "feature")
+	require.NoError(t, cfg.Load(base, WithTrust(remote, TrustLow)))
+
+	var got map[string]any
+	require.NoError(t, cfg.Unmarshal(&got))
+	assert.Equal(t, map[string]any{
+		"auth":    map[string]any{"enabled": true, "users": []any{"admin"}},
+		"feature": false,
+	}, got)
+
+	rejected := cfg.LoadReport().RejectedKeys
+	require.Len(t, rejected, 3)
+	assert.Equal(t, "auth", rejected[0].Path)
+	assert.Equal(t, `trust level low below high required by "auth"`, rejected[0].Reason)
+
+	// 可信级别足够时不受限制
+	require.NoError(t, cfg.Load(base, WithTrust(remote, TrustHigh)))
+	_, ok := cfg.Get("feature")
+	assert.False(t, ok)
+}
+
+func TestKeyGuard_AncestorOverwrite(t *testing.T) {
+	base := Prioritized("base", PriorityFiles, &switchSource{data: `{"auth":{"enabled":true,"realm":"corp"},"log":{"level":"info"}}`})
+	protected := map[string]any{"enabled": true, "realm": "corp"}
+
+	t.Run("ScalarOverwrite", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+		cfg.RequireTrust(TrustHigh, "auth.enabled")
+		low := Prioritized("remote", PriorityRemote, &switchSource{data: `{"auth":"off","log":"debug"}`})
+		require.NoError(t, cfg.Load(base, WithTrust(low, TrustLow)))
+
+		var auth map[string]any
+		require.NoError(t, cfg.UnmarshalKey("auth", &auth))
+		assert.Equal(t, protected, auth)
+		level, _ := cfg.GetString("log")
+		assert.Equal(t, "debug", level)
+		assert.Equal(t, []RejectedKey{{
+			Path:   "auth",
+			Source: "remote",
+			Reason: `trust level low below high required by "auth.enabled" (would overwrite "auth.enabled")`,
+		}}, cfg.LoadReport().RejectedKeys)
+	})
+
+	t.Run("Directives", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}), WithMergeDirectives())
+		override := &switchSource{data: `{"auth":{"$replace":true,"realm":"public"},"$delete":["auth"]}`}
+		require.NoError(t, cfg.Load(base, WithKeyFilter(override, KeyFilter{Deny: []string{"auth.enabled"}})))
+
+		// $replace 被拒绝后其余 key 按普通深度合并处理
+		var auth map[string]any
+		require.NoError(t, cfg.UnmarshalKey("auth", &auth))
+		assert.Equal(t, map[string]any{"enabled": true, "realm": "public"}, auth)
+		assert.Len(t, cfg.LoadReport().RejectedKeys, 2)
+	})
+
+	t.Run("JSONPatch", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+		patch := &switchSource{data: `[
+			{"op": "replace", "path": "/auth", "value": "off"},
+			{"op": "remove", "path": "/auth"},
+			{"op": "replace", "path": "/log/level", "value": "debug"}
+		]`}
+		require.NoError(t, cfg.Load(base, WithKeyFilter(JSONPatch(patch), KeyFilter{Deny: []string{"auth.enabled"}})))
+
+		var auth map[string]any
+		require.NoError(t, cfg.UnmarshalKey("auth", &auth))
+		assert.Equal(t, protected, auth)
+		level, _ := cfg.GetString("log.level")
+		assert.Equal(t, "debug", level)
+		assert.Len(t, cfg.LoadReport().RejectedKeys, 2)
+	})
+}
@@ -0,0 +1,368 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// kubeDataDir 是 Kubernetes 卷中指向当前版本目录的符号链接。
+// kubelet 更新 ConfigMap / Secret 时写入新的 ..<timestamp> 目录，再原子地替换 ..data 链接。
+const kubeDataDir = "..data"
+
+// KubeVolumeSource 读取 Kubernetes 以卷方式挂载的 ConfigMap / Secret。
+//
+// 默认每个文件对应一个配置 key（文件名中的 "." 表示层级，例如 db.host => db.host），值为文件内容，
+// 末尾的一个换行符会被去掉。开启 WithKubeVolumeDecodeFiles 后，扩展名为 .yaml / .json / .toml 等的文件
+// 按各自的格式解析，每个文件成为独立的层。
+//
+// 每一层的文件都从 ..data 指向的同一个版本目录读取，不会读到一半旧、一半新的内容；Metadata.Revision 为该目录名。
+// 读取期间 kubelet 切换版本并删除旧目录时，自动改为读取新版本。
+// 没有 ..data 链接的普通目录（例如本地开发）直接读取目录中的文件。
+type KubeVolumeSource struct {
+	dir         string
+	name        string
+	prefix      string
+	decodeFiles bool
+}
+
+var _ LayeredSource = (*KubeVolumeSource)(nil)
+
+// KubeVolumeOption 用于在 NewKubeVolumeSource 中配置 KubeVolumeSource 的可选参数。
+type KubeVolumeOption func(*KubeVolumeSource)
+
+// WithKubeVolumeName 指定该 Source 的名称，默认为挂载目录。
+func WithKubeVolumeName(name string) KubeVolumeOption {
+	return func(k *KubeVolumeSource) {
+		if strings.TrimSpace(name) != "" {
+			k.name = name
+		}
+	}
+}
+
+// WithKubeVolumeKeyPrefix 为文件对应的 key 添加路径前缀，例如 "db" 时文件 password 对应 db.password。
+func WithKubeVolumeKeyPrefix(prefix string) KubeVolumeOption {
+	return func(k *KubeVolumeSource) {
+		k.prefix = strings.Trim(strings.TrimSpace(prefix), ".")
+	}
+}
+
+// WithKubeVolumeDecodeFiles 按扩展名解析已知格式的文件（app.yaml、db.json 等），每个文件成为独立的层；
+// 其余文件仍按一个文件一个 key 处理，并在这些文件层之前合并。
+func WithKubeVolumeDecodeFiles() KubeVolumeOption {
+	return func(k *KubeVolumeSource) {
+		k.decodeFiles = true
+	}
+}
+
+// NewKubeVolumeSource 创建读取 Kubernetes ConfigMap / Secret 挂载目录的配置源：
+//
+//	// ConfigMap 中的 app.yaml 按 YAML 解析，LOG_LEVEL 等其他 key 各自成为一个配置项
+//	src := NewKubeVolumeSource("/etc/config", WithKubeVolumeDecodeFiles())
+//	_ = cfg.Load(NewFileSource("config/app.yaml"), src)
+//
+//	// 配合 KubeVolumeWatcher 在 ConfigMap 更新后重新加载
+//	go NewKubeVolumeWatcher("/etc/config", 0).Start(ctx, func() { _ = cfg.Load(...) })
+func NewKubeVolumeSource(dir string, opts ...KubeVolumeOption) *KubeVolumeSource {
+	k := &KubeVolumeSource{dir: strings.TrimSpace(dir)}
+	for _, opt := range opts {
+		opt(k)
+	}
+	if k.name == "" {
+		k.name = k.dir
+	}
+	return k
+}
+
+// Name 返回该 Source 的名称。
+func (k *KubeVolumeSource) Name() string {
+	return k.name
+}
+
+// Expand 实现 LayeredSource 接口。开启 WithKubeVolumeDecodeFiles 且存在可解析的文件时，
+// 返回 key 文件层（如果有）与各个可解析文件的层，否则返回 nil，由 Load 读取全部 key 文件。
+func (k *KubeVolumeSource) Expand(ctx context.Context) ([]Source, error) {
+	if !k.decodeFiles {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	root, _, err := k.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	keys, files, err := k.listFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	// 各层在 Expand 之后才被读取，期间 kubelet 可能已经切换并删除了 root，因此只记录文件名，
+	// 读取时再通过 readSnapshot 定位当前版本目录
+	var out []Source
+	if len(keys) > 0 {
+		out = append(out, &kubeKeysSource{src: k, keys: keys})
+	}
+	for _, f := range files {
+		out = append(out, &kubeFileSource{src: k, file: f})
+	}
+	return out, nil
+}
+
+// Load 实现 Source 接口。
+func (k *KubeVolumeSource) Load() ([]byte, Metadata, error) {
+	return k.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口，把 key 文件转换为 JSON 交给 JSON Decoder 解析。
+func (k *KubeVolumeSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+	return k.readSnapshot(func(root, revision string) ([]byte, Metadata, error) {
+		keys, files, err := k.listFiles(root)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+		if len(files) > 0 {
+			// Expand 之后目录被更新，可解析的文件不能作为 key 处理
+			return nil, Metadata{}, fmt.Errorf("KubeVolumeSource: %q changed during load", k.dir)
+		}
+		return k.loadKeys(root, revision, keys)
+	})
+}
+
+// readSnapshot 在当前版本目录上执行 read。kubelet 替换 ..data 之后会删除旧的版本目录，
+// 读取期间发生切换导致文件不存在时，以新的版本目录重试一次。
+func (k *KubeVolumeSource) readSnapshot(read func(root, revision string) ([]byte, Metadata, error)) ([]byte, Metadata, error) {
+	root, revision, err := k.snapshot()
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	data, meta, err := read(root, revision)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return data, meta, err
+	}
+	current, currentRevision, serr := k.snapshot()
+	if serr != nil || currentRevision == revision {
+		// 版本没有变化，文件确实不存在
+		return nil, Metadata{}, err
+	}
+	return read(current, currentRevision)
+}
+
+// snapshot 返回当前版本目录与版本标识。
+func (k *KubeVolumeSource) snapshot() (string, string, error) {
+	if k.dir == "" {
+		return "", "", fmt.Errorf("KubeVolumeSource: dir is empty")
+	}
+	target, err := os.Readlink(filepath.Join(k.dir, kubeDataDir))
+	if err != nil {
+		if _, statErr := os.Stat(k.dir); statErr != nil {
+			return "", "", fmt.Errorf("KubeVolumeSource: read dir %q failed: %w", k.dir, statErr)
+		}
+		return k.dir, "", nil
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(k.dir, target)
+	}
+	return target, filepath.Base(target), nil
+}
+
+// listFiles 返回 root 中的 key 文件与（开启 WithKubeVolumeDecodeFiles 时）可解析的文件，按文件名排序。
+// kubelet 使用的 .. 开头的目录与链接会被忽略。
+func (k *KubeVolumeSource) listFiles(root string) (keys, files []string, err error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("KubeVolumeSource: read dir %q failed: %w", root, err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, "..") {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(root, name))
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if k.decodeFiles {
+			if _, err := detectFormatFromPath(name); err == nil {
+				files = append(files, name)
+				continue
+			}
+		}
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	sort.Strings(files)
+	return keys, files, nil
+}
+
+// loadKeys 读取 key 文件并序列化为 JSON。
+func (k *KubeVolumeSource) loadKeys(root, revision string, keys []string) ([]byte, Metadata, error) {
+	out := make(map[string]any, len(keys))
+	for _, key := range keys {
+		b, err := os.ReadFile(filepath.Join(root, key))
+		if err != nil {
+			return nil, Metadata{}, fmt.Errorf("KubeVolumeSource: read key %q failed: %w", key, err)
+		}
+		insertNestedValue(out, strings.Split(joinPath(k.prefix, key), "."), trimTrailingNewline(string(b)))
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	return data, Metadata{Format: "json", Source: k.name, Revision: revision}, nil
+}
+
+// kubeKeysSource 是 WithKubeVolumeDecodeFiles 时 key 文件组成的层。
+type kubeKeysSource struct {
+	src  *KubeVolumeSource
+	keys []string
+}
+
+var _ ContextSource = (*kubeKeysSource)(nil)
+
+// Name 返回所属 KubeVolumeSource 的名称。
+func (s *kubeKeysSource) Name() string {
+	return s.src.name
+}
+
+// Load 实现 Source 接口。
+func (s *kubeKeysSource) Load() ([]byte, Metadata, error) {
+	return s.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (s *kubeKeysSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+	return s.src.readSnapshot(func(root, revision string) ([]byte, Metadata, error) {
+		return s.src.loadKeys(root, revision, s.keys)
+	})
+}
+
+// kubeFileSource 是 WithKubeVolumeDecodeFiles 时一个可解析文件组成的层，从当前版本目录读取，
+// Metadata.Revision 为该版本目录名。
+type kubeFileSource struct {
+	src  *KubeVolumeSource
+	file string
+}
+
+var _ ContextSource = (*kubeFileSource)(nil)
+
+// Name 返回文件在挂载目录中的路径。
+func (s *kubeFileSource) Name() string {
+	return filepath.Join(s.src.dir, s.file)
+}
+
+// includeSource 返回挂载目录中该文件的 FileSource（经由 kubelet 的符号链接），只用于解析 $include 的相对路径，
+// 不依赖会被删除的版本目录。挂载卷是只读的，因此不通过 Unwrap 暴露，避免被当作可写回的本地文件记录摘要。
+func (s *kubeFileSource) includeSource() *FileSource {
+	return NewFileSource(s.Name())
+}
+
+// Load 实现 Source 接口。
+func (s *kubeFileSource) Load() ([]byte, Metadata, error) {
+	return s.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (s *kubeFileSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	return s.src.readSnapshot(func(root, revision string) ([]byte, Metadata, error) {
+		data, meta, err := NewFileSource(filepath.Join(root, s.file), WithFileSourceName(s.Name())).LoadContext(ctx)
+		meta.Revision = revision
+		return data, meta, err
+	})
+}
+
+// trimTrailingNewline 去掉末尾的一个换行符（\n 或 \r\n）。
+func trimTrailingNewline(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
+
+// KubeVolumeWatcher 监听 Kubernetes 挂载目录的更新，在 ..data 链接被替换时触发回调。
+// kubelet 每次更新只替换一次链接，因此每次更新只触发一次回调，不会因为多个文件变化而重复加载。
+// 没有 ..data 链接的普通目录按文件名、大小与修改时间检测变化。
+type KubeVolumeWatcher struct {
+	dir    string
+	period time.Duration
+}
+
+// defaultKubeWatchPeriod 是 KubeVolumeWatcher 的默认检查间隔。
+const defaultKubeWatchPeriod = 2 * time.Second
+
+// NewKubeVolumeWatcher 创建挂载目录的 watcher，period <= 0 时每 2 秒检查一次。
+// kubelet 同步 ConfigMap 本身有分钟级的延迟，秒级的检查间隔已经足够。
+func NewKubeVolumeWatcher(dir string, period time.Duration) *KubeVolumeWatcher {
+	if period <= 0 {
+		period = defaultKubeWatchPeriod
+	}
+	return &KubeVolumeWatcher{dir: dir, period: period}
+}
+
+// Start 开始监听，阻塞直到 ctx 结束。onChange 在检测到更新时被调用，通常在其中重新调用 Config.Load。
+func (w *KubeVolumeWatcher) Start(ctx context.Context, onChange func()) error {
+	if w.dir == "" {
+		return fmt.Errorf("KubeVolumeWatcher: dir is empty")
+	}
+	if onChange == nil {
+		return fmt.Errorf("KubeVolumeWatcher: onChange callback is nil")
+	}
+
+	last, err := w.fingerprint()
+	if err != nil {
+		return fmt.Errorf("KubeVolumeWatcher: read dir %q failed: %w", w.dir, err)
+	}
+
+	ticker := time.NewTicker(w.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			cur, err := w.fingerprint()
+			if err != nil {
+				// 目录暂时不可读（例如卷正在重新挂载），下次再检查
+				continue
+			}
+			if cur != last {
+				last = cur
+				onChange()
+			}
+		}
+	}
+}
+
+// fingerprint 返回目录当前的版本标识：..data 链接的目标，或普通目录中文件的名称、大小与修改时间。
+func (w *KubeVolumeWatcher) fingerprint() (string, error) {
+	if target, err := os.Readlink(filepath.Join(w.dir, kubeDataDir)); err == nil {
+		return target, nil
+	}
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s:%d:%d;", e.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+// writeKubeVolume 按 kubelet 的方式写入一个新版本：写 ..<version> 目录，再原子替换 ..data 链接。
+func writeKubeVolume(t *testing.T, dir, version string, files map[string]string) {
+	t.Helper()
+	versionDir := filepath.Join(dir, ".."+version)
+	require.NoError(t, os.MkdirAll(versionDir, 0o755))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(versionDir, name), []byte(content), 0o644))
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); err != nil {
+			require.NoError(t, os.Symlink(filepath.Join(kubeDataDir, name), link))
+		}
+	}
+	tmp := filepath.Join(dir, "..data_tmp")
+	require.NoError(t, os.Symlink(".."+version, tmp))
+	require.NoError(t, os.Rename(tmp, filepath.Join(dir, kubeDataDir)))
+}
+
+func TestKubeVolumeSource(t *testing.T) {
+	dir := t.TempDir()
+	writeKubeVolume(t, dir, "2024_01_01", map[string]string{
+		"db.host":   "db.internal\n",
+		"LOG_LEVEL": "info",
+		"app.yaml":  "server:\n  port: 8080\n",
+	})
+
+	t.Run("file per key", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+		require.NoError(t, cfg.Load(NewKubeVolumeSource(dir, WithKubeVolumeKeyPrefix("k8s"))))
+
+		host, _ := cfg.GetString("k8s.db.host")
+		level, _ := cfg.GetString("k8s.LOG_LEVEL")
+		yaml, _ := cfg.GetString("k8s.app.yaml")
+		assert.Equal(t, "db.internal", host)
+		assert.Equal(t, "info", level)
+		assert.Equal(t, "server:\n  port: 8080", yaml)
+		assert.Equal(t, "..2024_01_01", cfg.Layers()[0].Revision)
+	})
+
+	t.Run("decode files", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}), WithDecoder(decoder.YAMLDecoder{}))
+		require.NoError(t, cfg.Load(NewKubeVolumeSource(dir, WithKubeVolumeDecodeFiles(), WithKubeVolumeName("configmap"))))
+
+		port, _ := cfg.GetInt("server.port")
+		host, _ := cfg.GetString("db.host")
+		assert.Equal(t, 8080, port)
+		assert.Equal(t, "db.internal", host)
+
+		layers := cfg.Layers()
+		require.Len(t, layers, 2)
+		assert.Equal(t, "configmap", layers[0].Name)
+		assert.Equal(t, filepath.Join(dir, "app.yaml"), layers[1].Name)
+		pos, _ := cfg.Position("server.port")
+		assert.Equal(t, filepath.Join(dir, "app.yaml"), pos.Source)
+		assert.Equal(t, "..2024_01_01", layers[0].Revision)
+		assert.Equal(t, "..2024_01_01", layers[1].Revision)
+
+		// 挂载卷是只读的，文件层不能被当作可写回的本地文件
+		assert.Empty(t, cfg.fileDigests)
+	})
+
+	t.Run("version swapped after expand", func(t *testing.T) {
+		dir := t.TempDir()
+		writeKubeVolume(t, dir, "v1", map[string]string{"LOG_LEVEL": "info", "app.yaml": "server:\n  port: 8080\n"})
+		src := NewKubeVolumeSource(dir, WithKubeVolumeDecodeFiles())
+		parts, err := src.Expand(context.Background())
+		require.NoError(t, err)
+		require.Len(t, parts, 2)
+
+		// kubelet 写入新版本并删除旧的版本目录
+		writeKubeVolume(t, dir, "v2", map[string]string{"LOG_LEVEL": "debug", "app.yaml": "server:\n  port: 9090\n"})
+		require.NoError(t, os.RemoveAll(filepath.Join(dir, "..v1")))
+
+		data, meta, err := parts[0].Load()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"LOG_LEVEL":"debug"}`, string(data))
+		assert.Equal(t, "..v2", meta.Revision)
+
+		data, meta, err = parts[1].Load()
+		require.NoError(t, err)
+		assert.Equal(t, "server:\n  port: 9090\n", string(data))
+		assert.Equal(t, "..v2", meta.Revision)
+		assert.Equal(t, filepath.Join(dir, "app.yaml"), meta.Source)
+
+		_, ok := findSource[*FileSource](parts[1])
+		assert.False(t, ok)
+		f, ok := includeBase(parts[1])
+		require.True(t, ok)
+		assert.Equal(t, filepath.Join(dir, "app.yaml"), f.path)
+	})
+
+	t.Run("plain dir", func(t *testing.T) {
+		plain := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(plain, "name"), []byte("demo\r\n"), 0o644))
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+		require.NoError(t, cfg.Load(NewKubeVolumeSource(plain)))
+		name, _ := cfg.GetString("name")
+		assert.Equal(t, "demo", name)
+
+		assert.Error(t, cfg.Load(NewKubeVolumeSource(filepath.Join(plain, "missing"))))
+	})
+}
+
+func TestKubeVolumeWatcher(t *testing.T) {
+	dir := t.TempDir()
+	writeKubeVolume(t, dir, "v1", map[string]string{"a": "1", "b": "1"})
+
+	var calls atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewKubeVolumeWatcher(dir, 5*time.Millisecond).Start(ctx, func() { calls.Add(1) })
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Zero(t, calls.Load())
+
+	// 一次更新修改了多个文件，只触发一次回调
+	writeKubeVolume(t, dir, "v2", map[string]string{"a": "2", "b": "2"})
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, 5*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+
+	writeKubeVolume(t, dir, "v3", map[string]string{"a": "3"})
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultLoadConcurrency 是默认同时拉取的 Source 数量。
+const defaultLoadConcurrency = 4
+
+// WithLoadConcurrency 设置 Load 时同时拉取 Source 的最大数量，n <= 1 表示逐个拉取。
+//
+// 并发只作用于拉取阶段（网络 IO），解析与合并仍按层栈顺序进行，合并结果与并发度无关。
+// 多个远程 Source（etcd、HTTP、多个 Apollo namespace）时可以显著缩短冷启动时间。
+func WithLoadConcurrency(n int) Option {
+	return func(c *DefaultConfig) {
+		if n < 1 {
+			n = 1
+		}
+		c.concurrency = n
+	}
+}
+
+// LayerInfo 描述最近一次 Load 中的一个配置层，按合并顺序排列，用于调试配置的来源。
+type LayerInfo struct {
+	// Name 是 Source 名称（Metadata.Source）
+	Name string
+	// Format 是该层内容的格式，被跳过的层可能为空
+	Format string
+	// Priority 是该层的优先级，见 Prioritized
+	Priority int
+	// Revision 是后端提供的版本标识，见 Metadata.Revision
+	Revision string
+	// Profile 是该层对应的 profile，只有 WithProfiles 展开的层非空
+	Profile string
+	// Bytes 是该层原始内容的字节数
+	Bytes int
+	// Duration 是拉取该层内容（Source.Load）的耗时，不含解析与合并
+	Duration time.Duration
+	// Skipped 表示该层被跳过（例如可选文件不存在），没有参与合并
+	Skipped bool
+	// Reason 是被跳过的原因
+	Reason string
+	// Required 表示该层失败时会中断 Load，见 Required / BestEffort
+	Required bool
+	// Failed 表示该层加载、解析或合并失败，没有参与合并
+	Failed bool
+	// Err 是该层失败的错误
+	Err error
+}
+
+// Layers 返回最近一次成功 Load 的配置层列表（含被跳过的层），按合并顺序（优先级从低到高）排列。
+func (c *DefaultConfig) Layers() []LayerInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make([]LayerInfo, len(c.layers))
+	copy(out, c.layers)
+	return out
+}
+
+// fetchResult 是单个 Source 的拉取结果。
+type fetchResult struct {
+	data     []byte
+	meta     Metadata
+	err      error
+	duration time.Duration
+}
+
+// fetchAll 以有界并发拉取层栈中的全部 Source，结果与 stack 下标一一对应。
+func (c *DefaultConfig) fetchAll(ctx context.Context, stack []stackEntry) []fetchResult {
+	results := make([]fetchResult, len(stack))
+	fetch := func(i int) {
+		start := time.Now()
+		data, meta, err := loadSource(ctx, stack[i].src)
+		results[i] = fetchResult{data: data, meta: meta, err: err, duration: time.Since(start)}
+	}
+
+	workers := c.concurrency
+	if workers > len(stack) {
+		workers = len(stack)
+	}
+	if workers <= 1 {
+		for i := range stack {
+			fetch(i)
+		}
+		return results
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fetch(i)
+			}
+		}()
+	}
+	for i := range stack {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// LayeredSource 是 Load 时展开为多个层的 Source，例如 DirSource。
+// 展开出的每个子 Source 都是独立的层，拥有自己的 LayerInfo 与位置信息，并继承 LayeredSource 的优先级与装饰器。
+type LayeredSource interface {
+	Source
+
+	// Expand 返回按合并顺序排列的子 Source。返回错误或没有子 Source 时，
+	// LayeredSource 本身作为一个层参与加载，由其 Load 提供内容或返回对应的错误。
+	Expand(ctx context.Context) ([]Source, error)
+}
+
+// layerPart 是 LayeredSource 展开出的单个层。
+type layerPart struct {
+	parent Source
+	src    Source
+}
+
+var _ ContextSource = (*layerPart)(nil)
+
+// Name 返回子 Source 的名称。
+func (p *layerPart) Name() string {
+	return sourceName(p.src, Metadata{})
+}
+
+// Unwrap 返回子 Source。
+func (p *layerPart) Unwrap() Source {
+	return p.src
+}
+
+// Parent 返回展开前的 Source（含其装饰器），见 findSource。
+func (p *layerPart) Parent() Source {
+	return p.parent
+}
+
+// Load 实现 Source 接口。
+func (p *layerPart) Load() ([]byte, Metadata, error) {
+	return p.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (p *layerPart) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	return loadSource(ctx, p.src)
+}
+
+// expandLayers 把层栈中的 LayeredSource 替换为其展开出的子层，保持原有优先级与顺序。
+func expandLayers(ctx context.Context, stack []stackEntry) []stackEntry {
+	out := make([]stackEntry, 0, len(stack))
+	for _, entry := range stack {
+		l, ok := findSource[LayeredSource](entry.src)
+		if !ok {
+			out = append(out, entry)
+			continue
+		}
+		children, err := l.Expand(ctx)
+		if err != nil || len(children) == 0 {
+			out = append(out, entry)
+			continue
+		}
+		for _, child := range children {
+			out = append(out, stackEntry{src: &layerPart{parent: entry.src, src: child}, priority: entry.priority})
+		}
+	}
+	return out
+}
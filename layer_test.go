@@ -0,0 +1,72 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+// slowSource 在返回前休眠 delay，并统计同时处于 Load 中的数量。
+type slowSource struct {
+	name     string
+	delay    time.Duration
+	inflight *int32
+	peak     *int32
+}
+
+func (s slowSource) Load() ([]byte, Metadata, error) {
+	return s.LoadContext(context.Background())
+}
+
+func (s slowSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	n := atomic.AddInt32(s.inflight, 1)
+	defer atomic.AddInt32(s.inflight, -1)
+	for {
+		p := atomic.LoadInt32(s.peak)
+		if n <= p || atomic.CompareAndSwapInt32(s.peak, p, n) {
+			break
+		}
+	}
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, Metadata{}, ctx.Err()
+	}
+	return []byte(fmt.Sprintf(`{"winner":%q}`, s.name)), Metadata{Format: "json", Source: s.name}, nil
+}
+
+func TestDefaultConfig_LoadConcurrency(t *testing.T) {
+	var inflight, peak int32
+	var sources []Source
+	for i := 0; i < 6; i++ {
+		// 越靠后的 Source 越快返回，合并顺序仍应以层栈为准
+		sources = append(sources, slowSource{
+			name:     fmt.Sprintf("s%d", i),
+			delay:    time.Duration(60-i*10) * time.Millisecond,
+			inflight: &inflight,
+			peak:     &peak,
+		})
+	}
+
+	cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}), WithLoadConcurrency(3))
+	require.NoError(t, cfg.Load(sources...))
+
+	winner, _ := cfg.GetString("winner")
+	assert.Equal(t, "s5", winner)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&peak))
+
+	layers := cfg.Layers()
+	require.Len(t, layers, 6)
+	for i, l := range layers {
+		assert.Equal(t, fmt.Sprintf("s%d", i), l.Name)
+		assert.Positive(t, l.Duration)
+		assert.Equal(t, len(`{"winner":"s0"}`), l.Bytes)
+	}
+}
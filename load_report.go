@@ -0,0 +1,155 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WithContinueOnError 开启容错加载：未通过 Required / BestEffort 显式标记的 Source 均视为 best-effort，
+// 某个 Source 加载、解析或合并失败时跳过该层，其余层照常合并。
+//
+// 默认（未开启时）所有 Source 都是 required，任一失败都会中断 Load 且保持当前配置不变。
+func WithContinueOnError() Option {
+	return func(c *DefaultConfig) {
+		c.continueOnError = true
+	}
+}
+
+// RequirementSource 是 Required / BestEffort 返回的装饰器，标记 Source 失败时是否中断 Load。
+type RequirementSource struct {
+	src      Source
+	required bool
+}
+
+var _ ContextSource = (*RequirementSource)(nil)
+
+// Required 把 src 标记为必需：失败时 Load 中断，即使开启了 WithContinueOnError。
+func Required(src Source) *RequirementSource {
+	return &RequirementSource{src: src, required: true}
+}
+
+// BestEffort 把 src 标记为尽力而为：失败时跳过该层，错误汇总到 Load 返回的 *LoadError 中。
+//
+//	err := cfg.Load(
+//	    NewFileSource("config/base.yaml"),
+//	    BestEffort(NewHTTPSource("http://feature-flags/app.json")),
+//	)
+//	var le *LoadError
+//	if errors.As(err, &le) {
+//	    log.Printf("partially loaded: %v", err) // 配置已生效，只是缺少失败的层
+//	} else if err != nil {
+//	    return err
+//	}
+func BestEffort(src Source) *RequirementSource {
+	return &RequirementSource{src: src, required: false}
+}
+
+// Required 报告 Source 是否为必需。
+func (r *RequirementSource) Required() bool {
+	return r.required
+}
+
+// Name 返回被包装 Source 的名称。
+func (r *RequirementSource) Name() string {
+	return sourceName(r.src, Metadata{})
+}
+
+// Unwrap 返回被包装的原始 Source。
+func (r *RequirementSource) Unwrap() Source {
+	return r.src
+}
+
+// Load 实现 Source 接口。
+func (r *RequirementSource) Load() ([]byte, Metadata, error) {
+	return r.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (r *RequirementSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	return loadSource(ctx, r.src)
+}
+
+// sourceRequired 返回 src 是否为必需，未标记时返回 def。
+func sourceRequired(src Source, def bool) bool {
+	if r, ok := findSource[interface{ Required() bool }](src); ok {
+		return r.Required()
+	}
+	return def
+}
+
+// LoadError 表示 Load 部分成功：所有 required Source 均已生效，但有 best-effort Source 失败。
+// Errors 中每个错误都带有失败的 Source 名称（*SourceError 或 *DecodeError），
+// 可以通过 errors.Is / errors.As 逐个匹配。
+type LoadError struct {
+	Errors []error
+}
+
+func (e *LoadError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d best-effort source(s) failed", len(e.Errors))
+	for _, err := range e.Errors {
+		b.WriteString("\n  - ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+func (e *LoadError) Unwrap() []error {
+	return e.Errors
+}
+
+// LoadReport 描述最近一次 Load 的结果，无论成功与否。
+type LoadReport struct {
+	// StartedAt 是 Load 开始的时间
+	StartedAt time.Time
+	// Duration 是 Load 的总耗时
+	Duration time.Duration
+	// Layers 是本次参与加载的层，按合并顺序排列；Load 中断时只包含中断前处理过的层
+	Layers []LayerInfo
+	// Conflicts 是本次合并中检测到的类型冲突，见 WithTypeConflictPolicy
+	Conflicts []TypeConflict
+	// LockViolations 是本次合并中对被锁定 key 的覆盖尝试，见 LockKeys
+	LockViolations []LockViolation
+	// RejectedKeys 是被 KeyFilter 或可信级别丢弃的 key，见 WithKeyFilter、RequireTrust
+	RejectedKeys []RejectedKey
+	// Profiles 是本次激活的 profile 及其来源，见 WithProfiles
+	Profiles ProfileResolution
+	// Err 是 Load 返回的错误
+	Err error
+}
+
+// Failed 返回加载失败的层。
+func (r LoadReport) Failed() []LayerInfo {
+	var out []LayerInfo
+	for _, l := range r.Layers {
+		if l.Failed {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// Applied 报告本次 Load 的结果是否已生效（成功或部分成功）。
+func (r LoadReport) Applied() bool {
+	if r.Err == nil {
+		return !r.StartedAt.IsZero()
+	}
+	var le *LoadError
+	return errors.As(r.Err, &le)
+}
+
+// LoadReport 返回最近一次 Load 的报告。与 Layers 不同，失败的 Load 同样会更新报告。
+func (c *DefaultConfig) LoadReport() LoadReport {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	r := c.report
+	r.Layers = append([]LayerInfo(nil), r.Layers...)
+	r.Conflicts = append([]TypeConflict(nil), r.Conflicts...)
+	r.LockViolations = append([]LockViolation(nil), r.LockViolations...)
+	r.RejectedKeys = append([]RejectedKey(nil), r.RejectedKeys...)
+	return r
+}
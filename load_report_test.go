@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestDefaultConfig_ContinueOnError(t *testing.T) {
+	boom := errors.New("boom")
+	broken := &switchSource{err: boom}
+	badJSON := &switchSource{data: `{"a":`}
+
+	t.Run("BestEffort", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}), WithDecoder(decoder.JSONDecoder{}))
+		err := cfg.Load(
+			NewFileSource("testdata/base.yaml"),
+			BestEffort(broken),
+			BestEffort(Prioritized("bad-json", PriorityRemote, badJSON)),
+		)
+
+		var le *LoadError
+		require.ErrorAs(t, err, &le)
+		assert.Len(t, le.Errors, 2)
+		assert.ErrorIs(t, err, boom)
+		var de *DecodeError
+		require.ErrorAs(t, err, &de)
+		assert.Equal(t, "bad-json", de.Source)
+
+		// 其余层照常生效
+		port, _ := cfg.GetInt("server.port")
+		assert.Equal(t, 8080, port)
+
+		report := cfg.LoadReport()
+		assert.True(t, report.Applied())
+		assert.Len(t, report.Layers, 3)
+		failed := report.Failed()
+		require.Len(t, failed, 2)
+		assert.Equal(t, "remote", failed[0].Name)
+		assert.ErrorIs(t, failed[0].Err, boom)
+		assert.False(t, failed[0].Required)
+		assert.True(t, report.Layers[0].Required)
+	})
+
+	t.Run("Mode", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}), WithDecoder(decoder.JSONDecoder{}), WithContinueOnError())
+		require.NoError(t, cfg.Load(NewFileSource("testdata/base.yaml")))
+
+		err := cfg.Load(NewFileSource("testdata/base.yaml"), broken)
+		var le *LoadError
+		require.ErrorAs(t, err, &le)
+
+		// Required 的层失败时中断 Load，当前配置保持不变
+		err = cfg.Load(Required(broken))
+		require.Error(t, err)
+		assert.False(t, errors.As(err, &le))
+		port, _ := cfg.GetInt("server.port")
+		assert.Equal(t, 8080, port)
+
+		report := cfg.LoadReport()
+		assert.False(t, report.Applied())
+		assert.ErrorIs(t, report.Err, boom)
+		require.Len(t, report.Failed(), 1)
+		assert.True(t, report.Failed()[0].Required)
+	})
+}
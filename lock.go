@@ -0,0 +1,199 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// LockPolicy 决定后续 Source 试图覆盖被锁定的 key 时如何处理。
+type LockPolicy int
+
+const (
+	// LockIgnore 是默认行为：保留被锁定的值，丢弃覆盖，并输出警告
+	LockIgnore LockPolicy = iota
+	// LockReject 把覆盖尝试视为该层的合并错误（*LockViolationError）
+	LockReject
+)
+
+// LockViolation 描述一次对被锁定 key 的覆盖尝试。
+type LockViolation struct {
+	// Path 是被锁定的路径
+	Path string
+	// Source 是试图覆盖的 Source
+	Source string
+}
+
+// LockViolationError 是 LockReject 策略下合并失败的错误。
+type LockViolationError struct {
+	Violations []LockViolation
+}
+
+func (e *LockViolationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = fmt.Sprintf("%s (from %q)", v.Path, v.Source)
+	}
+	return "attempt to override locked keys: " + strings.Join(parts, ", ")
+}
+
+// WithLockPolicy 设置覆盖被锁定 key 时的处理策略，默认 LockIgnore。
+func WithLockPolicy(p LockPolicy) Option {
+	return func(c *DefaultConfig) {
+		c.lockPolicy = p
+	}
+}
+
+// LockKeys 锁定匹配 patterns 的配置路径：路径一旦由某个层设置，后续层（包括 env、远程配置中心）
+// 都不能再修改、删除或通过覆盖父节点的方式抹掉它。适用于 TLS 模式、鉴权开关、管理端点等安全相关配置：
+//
+//	cfg.LockKeys("server.tls.mode", "auth.enabled", "admin.**")
+//
+// 每次覆盖尝试都会通过 Logger 输出警告（含试图覆盖的 Source），并记录在 LoadReport.LockViolations 中。
+// 锁定只作用于 Load 过程中的层合并，运行期覆盖（Set）不受限制。在下一次 Load 时生效。
+func (c *DefaultConfig) LockKeys(patterns ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.locked = append(c.locked, patterns...)
+}
+
+// LockingSource 是 Locked 返回的装饰器，该层合并后锁定其中的 key。
+type LockingSource struct {
+	src      Source
+	patterns []string
+}
+
+var _ ContextSource = (*LockingSource)(nil)
+
+// Locked 把 src 标记为锁定层：该层合并之后，匹配 patterns 的路径不能再被后续层覆盖；
+// 不指定 patterns 时锁定该层设置的所有 key。典型用途是由运维下发、不允许业务覆盖的安全基线：
+//
+//	cfg.Load(
+//	    Locked(NewFileSource("/etc/myapp/security.yaml")),
+//	    NewFileSource("config/app.yaml"),
+//	    NewEnvSource(WithEnvSourcePrefix("APP_")),
+//	)
+func Locked(src Source, patterns ...string) *LockingSource {
+	return &LockingSource{src: src, patterns: patterns}
+}
+
+// LockedKeys 返回该层锁定的路径模式，为空表示锁定该层设置的所有 key。
+func (l *LockingSource) LockedKeys() []string {
+	return l.patterns
+}
+
+// Name 返回被包装 Source 的名称。
+func (l *LockingSource) Name() string {
+	return sourceName(l.src, Metadata{})
+}
+
+// Unwrap 返回被包装的原始 Source。
+func (l *LockingSource) Unwrap() Source {
+	return l.src
+}
+
+// Load 实现 Source 接口。
+func (l *LockingSource) Load() ([]byte, Metadata, error) {
+	return l.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (l *LockingSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	return loadSource(ctx, l.src)
+}
+
+// sourceLockedKeys 返回 src 合并后需要锁定的路径模式。
+func sourceLockedKeys(src Source, data map[string]any) []string {
+	l, ok := findSource[interface{ LockedKeys() []string }](src)
+	if !ok {
+		return nil
+	}
+	if patterns := l.LockedKeys(); len(patterns) > 0 {
+		return patterns
+	}
+	var paths []string
+	flattenKeys("", data, &paths)
+	return paths
+}
+
+// lockedPaths 返回 data 中匹配 patterns 的所有节点路径（含中间节点），按字典序排列。
+func lockedPaths(data map[string]any, patterns []string) []string {
+	var out []string
+	var walk func(prefix string, m map[string]any)
+	walk = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			path := joinPath(prefix, k)
+			for _, p := range patterns {
+				if matchPathPattern(p, path) {
+					out = append(out, path)
+					break
+				}
+			}
+			if sub, ok := toStringMap(v); ok {
+				walk(path, sub)
+			}
+		}
+	}
+	walk("", data)
+	sort.Strings(out)
+	return out
+}
+
+// checkLocks 比较合并前后被锁定路径的值，返回被修改的路径（已去掉被修改祖先下的子路径）。
+// restore 为 true 时把这些路径恢复为合并前的值，并返回实际恢复的路径（见 restoreLocked）。
+func checkLocks(before, after map[string]any, paths []string, restore bool) (violated, restored []string) {
+next:
+	for _, p := range paths {
+		for _, v := range violated {
+			if strings.HasPrefix(p, v+".") {
+				continue next
+			}
+		}
+		old, _ := getByPath(before, p)
+		cur, ok := getByPath(after, p)
+		if ok && reflect.DeepEqual(old, cur) {
+			continue
+		}
+		violated = append(violated, p)
+		if restore {
+			restored = append(restored, restoreLocked(before, after, p))
+		}
+	}
+	return violated, restored
+}
+
+// restoreLocked 把 after 中的 path 恢复为 before 中的值，返回实际恢复的路径。
+// 如果 path 的某个祖先在 after 中已不是 map（被标量覆盖或被删除），只重建叶子会丢掉该祖先下的兄弟节点，
+// 因此从最浅的这种祖先开始整体恢复为合并前的子树。
+func restoreLocked(before, after map[string]any, path string) string {
+	parts := strings.Split(path, ".")
+	for i := 1; i < len(parts); i++ {
+		cur, ok := getByPath(after, strings.Join(parts[:i], "."))
+		if _, isMap := toStringMap(cur); !ok || !isMap {
+			parts = parts[:i]
+			break
+		}
+	}
+	p := strings.Join(parts, ".")
+	old, _ := getByPath(before, p)
+	insertNestedValue(after, parts, cloneValue(old))
+	return p
+}
+
+// savePositions 返回 paths 及其子路径当前的位置信息。
+func savePositions(positions map[string]Position, paths []string) map[string]Position {
+	if len(paths) == 0 {
+		return nil
+	}
+	out := make(map[string]Position)
+	for _, p := range paths {
+		for k, v := range positions {
+			if k == p || strings.HasPrefix(k, p+".") {
+				out[k] = v
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestLockKeys(t *testing.T) {
+	base := Prioritized("base", PriorityFiles, &switchSource{data: `{"server":{"tls":{"mode":"strict"},"port":80},"auth":{"enabled":true}}`})
+	env := Prioritized("env", PriorityEnv, &switchSource{data: `{"server":{"tls":{"mode":"off"},"port":8080},"auth":"none"}`})
+
+	t.Run("Ignore", func(t *testing.T) {
+		var buf bytes.Buffer
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}), WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+		cfg.LockKeys("server.tls.mode", "auth.enabled")
+		require.NoError(t, cfg.Load(base, env))
+
+		mode, _ := cfg.GetString("server.tls.mode")
+		assert.Equal(t, "strict", mode)
+		enabled, _ := cfg.GetBool("auth.enabled")
+		assert.True(t, enabled)
+		port, _ := cfg.GetInt("server.port")
+		assert.Equal(t, 8080, port)
+
+		pos, _ := cfg.Position("server.tls.mode")
+		assert.Equal(t, "base", pos.Source)
+		assert.Equal(t, []LockViolation{
+			{Path: "auth.enabled", Source: "env"},
+			{Path: "server.tls.mode", Source: "env"},
+		}, cfg.LoadReport().LockViolations)
+		assert.Contains(t, buf.String(), "path=server.tls.mode source=env locked_by=base")
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}), WithLockPolicy(LockReject))
+		cfg.LockKeys("server.tls.*")
+		err := cfg.Load(base, env)
+
+		var lve *LockViolationError
+		require.ErrorAs(t, err, &lve)
+		assert.Equal(t, []LockViolation{{Path: "server.tls.mode", Source: "env"}}, lve.Violations)
+	})
+
+	t.Run("LockedSource", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}), WithContinueOnError(), WithLockPolicy(LockReject))
+		err := cfg.Load(Locked(base), env, Prioritized("flags", PriorityFlags, &switchSource{data: `{"debug":true}`}))
+
+		var le *LoadError
+		require.ErrorAs(t, err, &le)
+		// env 层被拒绝，不影响其他层
+		port, _ := cfg.GetInt("server.port")
+		assert.Equal(t, 80, port)
+		debug, _ := cfg.GetBool("debug")
+		assert.True(t, debug)
+	})
+
+	t.Run("RestoreSiblings", func(t *testing.T) {
+		// 祖先被标量覆盖时整体恢复祖先，不丢失未锁定的兄弟节点
+		base := Prioritized("base", PriorityFiles, &switchSource{data: `{"server":{"tls":{"mode":"strict","cert":"a.pem"},"port":80}}`})
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+		cfg.LockKeys("server.tls.mode")
+		require.NoError(t, cfg.Load(base, Prioritized("env", PriorityEnv, &switchSource{data: `{"server":"off"}`})))
+
+		var got map[string]any
+		require.NoError(t, cfg.UnmarshalKey("server", &got))
+		assert.Equal(t, map[string]any{"tls": map[string]any{"mode": "strict", "cert": "a.pem"}, "port": float64(80)}, got)
+		assert.Equal(t, []LockViolation{{Path: "server.tls.mode", Source: "env"}}, cfg.LoadReport().LockViolations)
+		pos, _ := cfg.Position("server.port")
+		assert.Equal(t, "base", pos.Source)
+	})
+
+	t.Run("SameValue", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}), WithLockPolicy(LockReject))
+		cfg.LockKeys("server.**")
+		require.NoError(t, cfg.Load(base, &switchSource{data: `{"server":{"port":80}}`}))
+	})
+}
@@ -0,0 +1,242 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// MergeMode 决定某个路径上的值如何与已有值合并。
+type MergeMode string
+
+const (
+	// MergeDeep 是默认行为：map 递归合并，其他值（包括 list）直接替换
+	MergeDeep MergeMode = "deep"
+	// MergeReplace 整体替换，map 也不再递归合并
+	MergeReplace MergeMode = "replace"
+	// MergeAppend 把新 list 追加到已有 list 之后
+	MergeAppend MergeMode = "append"
+	// MergePrepend 把新 list 插入到已有 list 之前
+	MergePrepend MergeMode = "prepend"
+	// MergeUnion 追加并去重（保持首次出现的顺序）
+	MergeUnion MergeMode = "union"
+	// MergeByKey 把 list 中的 map 元素按 MergeRule.Key 字段匹配：
+	// 相同 key 的元素递归合并，新 key 的元素追加到末尾
+	MergeByKey MergeMode = "merge-by-key"
+)
+
+// MergeRule 为匹配 Path 的配置路径指定合并方式。
+// Path 支持通配符：* 匹配一段，** 匹配任意多段，例如 "features.*.items"、"**.tags"。
+type MergeRule struct {
+	Path string
+	Mode MergeMode
+	// Key 是 MergeByKey 模式下用于匹配 list 元素的字段名，例如 "name"
+	Key string
+}
+
+// RuleMergeStrategy 是按路径规则合并的 MergeStrategy，未匹配任何规则的路径使用 MergeDeep。
+type RuleMergeStrategy struct {
+	rules []MergeRule
+}
+
+var _ MergeStrategy = (*RuleMergeStrategy)(nil)
+
+// NewRuleMergeStrategy 创建按路径规则合并的策略，规则按顺序匹配，先匹配者生效：
+//
+//	ms, err := NewRuleMergeStrategy(
+//	    MergeRule{Path: "server.allowed_origins", Mode: MergeUnion},
+//	    MergeRule{Path: "features.experimental", Mode: MergeByKey, Key: "name"},
+//	)
+//	cfg := NewDefaultConfig(WithMergeStrategy(ms))
+//
+// 规则的 Mode 未知或 MergeByKey 规则缺少 Key 时返回错误，而不是等到合并时才失败。
+func NewRuleMergeStrategy(rules ...MergeRule) (*RuleMergeStrategy, error) {
+	for _, rule := range rules {
+		switch rule.Mode {
+		case MergeDeep, MergeReplace, MergeAppend, MergePrepend, MergeUnion:
+		case MergeByKey:
+			if rule.Key == "" {
+				return nil, fmt.Errorf("merge rule for %q: key is required for %s", rule.Path, MergeByKey)
+			}
+		default:
+			return nil, fmt.Errorf("merge rule for %q: unknown mode %q", rule.Path, rule.Mode)
+		}
+	}
+	return &RuleMergeStrategy{rules: append([]MergeRule(nil), rules...)}, nil
+}
+
+// Merge 实现 MergeStrategy 接口。
+func (r *RuleMergeStrategy) Merge(dst, src map[string]any) (map[string]any, error) {
+	return r.mergeMap("", dst, src)
+}
+
+// mode 返回 path 上第一个匹配的规则。
+func (r *RuleMergeStrategy) mode(path string) MergeRule {
+	for _, rule := range r.rules {
+		if matchPathPattern(rule.Path, path) {
+			return rule
+		}
+	}
+	return MergeRule{Mode: MergeDeep}
+}
+
+func (r *RuleMergeStrategy) mergeMap(prefix string, dst, src map[string]any) (map[string]any, error) {
+	if dst == nil {
+		dst = make(map[string]any)
+	}
+	for k, v := range src {
+		existing, ok := dst[k]
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		merged, err := r.mergeValue(joinPath(prefix, k), existing, v)
+		if err != nil {
+			return nil, err
+		}
+		dst[k] = merged
+	}
+	return dst, nil
+}
+
+func (r *RuleMergeStrategy) mergeValue(path string, dst, src any) (any, error) {
+	rule := r.mode(path)
+	if rule.Mode == MergeReplace {
+		return src, nil
+	}
+
+	if m1, ok := toStringMap(dst); ok {
+		if m2, ok := toStringMap(src); ok {
+			return r.mergeMap(path, m1, m2)
+		}
+		return src, nil
+	}
+
+	l1, ok1 := dst.([]any)
+	l2, ok2 := src.([]any)
+	if !ok1 || !ok2 {
+		return src, nil
+	}
+
+	switch rule.Mode {
+	case MergeAppend:
+		return append(append(make([]any, 0, len(l1)+len(l2)), l1...), l2...), nil
+	case MergePrepend:
+		return append(append(make([]any, 0, len(l1)+len(l2)), l2...), l1...), nil
+	case MergeUnion:
+		out := make([]any, 0, len(l1)+len(l2))
+		for _, v := range append(append([]any{}, l1...), l2...) {
+			if !containsValue(out, v) {
+				out = append(out, v)
+			}
+		}
+		return out, nil
+	case MergeByKey:
+		if rule.Key == "" {
+			return nil, fmt.Errorf("merge rule for %q: key is required for %s", rule.Path, MergeByKey)
+		}
+		return r.mergeListByKey(path, rule.Key, l1, l2)
+	case MergeDeep:
+		return src, nil
+	default:
+		return nil, fmt.Errorf("merge rule for %q: unknown mode %q", rule.Path, rule.Mode)
+	}
+}
+
+// mergeListByKey 按 key 字段合并两个 list；元素内部的路径以 list 路径为前缀继续匹配规则。
+func (r *RuleMergeStrategy) mergeListByKey(path, key string, dst, src []any) ([]any, error) {
+	out := append(make([]any, 0, len(dst)+len(src)), dst...)
+	for _, item := range src {
+		m2, ok := toStringMap(item)
+		id, hasID := m2[key]
+		if !ok || !hasID {
+			out = append(out, item)
+			continue
+		}
+
+		idx := -1
+		for i, existing := range out {
+			if m1, ok := toStringMap(existing); ok && reflect.DeepEqual(m1[key], id) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			out = append(out, item)
+			continue
+		}
+
+		m1, _ := toStringMap(out[idx])
+		merged, err := r.mergeMap(path, cloneMap(m1), m2)
+		if err != nil {
+			return nil, err
+		}
+		out[idx] = merged
+	}
+	return out, nil
+}
+
+func containsValue(list []any, v any) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeStrategySource 是 WithSourceMergeStrategy 返回的装饰器，为单个 Source 指定合并策略。
+type MergeStrategySource struct {
+	src Source
+	ms  MergeStrategy
+}
+
+var _ ContextSource = (*MergeStrategySource)(nil)
+
+// WithSourceMergeStrategy 指定 src 合并到已有配置时使用的策略，覆盖 WithMergeStrategy 设置的全局策略：
+//
+//	appendPlugins, err := NewRuleMergeStrategy(MergeRule{Path: "plugins", Mode: MergeAppend})
+//	if err != nil {
+//	    return err
+//	}
+//	cfg.Load(
+//	    NewFileSource("base.yaml"),
+//	    WithSourceMergeStrategy(NewFileSource("plugins.yaml"), appendPlugins),
+//	    NewEnvSource(WithEnvSourcePrefix("APP_")), // 使用全局策略，list 直接替换
+//	)
+func WithSourceMergeStrategy(src Source, ms MergeStrategy) *MergeStrategySource {
+	return &MergeStrategySource{src: src, ms: ms}
+}
+
+// MergeStrategy 返回该 Source 使用的合并策略。
+func (m *MergeStrategySource) MergeStrategy() MergeStrategy {
+	return m.ms
+}
+
+// Name 返回被包装 Source 的名称。
+func (m *MergeStrategySource) Name() string {
+	return sourceName(m.src, Metadata{})
+}
+
+// Unwrap 返回被包装的原始 Source。
+func (m *MergeStrategySource) Unwrap() Source {
+	return m.src
+}
+
+// Load 实现 Source 接口。
+func (m *MergeStrategySource) Load() ([]byte, Metadata, error) {
+	return m.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (m *MergeStrategySource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	return loadSource(ctx, m.src)
+}
+
+// sourceMergeStrategy 返回 src 的合并策略，未指定时返回 def。
+func sourceMergeStrategy(src Source, def MergeStrategy) MergeStrategy {
+	if m, ok := findSource[interface{ MergeStrategy() MergeStrategy }](src); ok && m.MergeStrategy() != nil {
+		return m.MergeStrategy()
+	}
+	return def
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestRuleMergeStrategy(t *testing.T) {
+	ms, err := NewRuleMergeStrategy(
+		MergeRule{Path: "append", Mode: MergeAppend},
+		MergeRule{Path: "prepend", Mode: MergePrepend},
+		MergeRule{Path: "union", Mode: MergeUnion},
+		MergeRule{Path: "features.experimental", Mode: MergeByKey, Key: "name"},
+		MergeRule{Path: "features.experimental.tags", Mode: MergeUnion},
+		MergeRule{Path: "db", Mode: MergeReplace},
+	)
+	require.NoError(t, err)
+
+	dst := map[string]any{
+		"append":  []any{1, 2},
+		"prepend": []any{1, 2},
+		"union":   []any{"a", "b"},
+		"other":   []any{1},
+		"db":      map[string]any{"host": "a", "port": 1},
+		"features": map[string]any{"experimental": []any{
+			map[string]any{"name": "x", "enabled": false, "tags": []any{"t1"}},
+			map[string]any{"name": "y", "enabled": true},
+		}},
+	}
+	src := map[string]any{
+		"append":  []any{3},
+		"prepend": []any{0},
+		"union":   []any{"b", "c"},
+		"other":   []any{2},
+		"db":      map[string]any{"host": "b"},
+		"features": map[string]any{"experimental": []any{
+			map[string]any{"name": "x", "enabled": true, "tags": []any{"t1", "t2"}},
+			map[string]any{"name": "z"},
+		}},
+	}
+
+	out, err := ms.Merge(dst, src)
+	require.NoError(t, err)
+	assert.Equal(t, []any{1, 2, 3}, out["append"])
+	assert.Equal(t, []any{0, 1, 2}, out["prepend"])
+	assert.Equal(t, []any{"a", "b", "c"}, out["union"])
+	assert.Equal(t, []any{2}, out["other"])
+	assert.Equal(t, map[string]any{"host": "b"}, out["db"])
+	assert.Equal(t, []any{
+		map[string]any{"name": "x", "enabled": true, "tags": []any{"t1", "t2"}},
+		map[string]any{"name": "y", "enabled": true},
+		map[string]any{"name": "z"},
+	}, out["features"].(map[string]any)["experimental"])
+
+	// 规则错误在构造时报告
+	_, err = NewRuleMergeStrategy(MergeRule{Path: "append", Mode: MergeByKey})
+	assert.ErrorContains(t, err, "key is required")
+	_, err = NewRuleMergeStrategy(MergeRule{Path: "append", Mode: "concat"})
+	assert.ErrorContains(t, err, "unknown mode")
+}
+
+func TestWithSourceMergeStrategy(t *testing.T) {
+	cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+	appendPlugins, err := NewRuleMergeStrategy(MergeRule{Path: "plugins", Mode: MergeAppend})
+	require.NoError(t, err)
+
+	require.NoError(t, cfg.Load(
+		&switchSource{data: `{"plugins":["a"]}`},
+		WithSourceMergeStrategy(&switchSource{data: `{"plugins":["b"]}`}, appendPlugins),
+	))
+	var plugins []string
+	require.NoError(t, cfg.UnmarshalKey("plugins", &plugins))
+	assert.Equal(t, []string{"a", "b"}, plugins)
+
+	// 未指定策略的 Source 使用全局策略，list 直接替换
+	require.NoError(t, cfg.Load(
+		&switchSource{data: `{"plugins":["a"]}`},
+		&switchSource{data: `{"plugins":["c"]}`},
+	))
+	require.NoError(t, cfg.UnmarshalKey("plugins", &plugins))
+	assert.Equal(t, []string{"c"}, plugins)
+}
+
+// partialMergeStrategy 在返回错误前修改 dst，模拟合并到一半失败的策略。
+type partialMergeStrategy struct{}
+
+func (partialMergeStrategy) Merge(dst, src map[string]any) (map[string]any, error) {
+	dst["plugins"] = []any{"half-merged"}
+	return nil, errors.New("merge failed")
+}
+
+func TestMergeFailureLeavesNoPartialWrite(t *testing.T) {
+	cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+	err := cfg.Load(
+		&switchSource{data: `{"plugins":["a"]}`},
+		BestEffort(WithSourceMergeStrategy(&switchSource{data: `{"plugins":["b"]}`}, partialMergeStrategy{})),
+	)
+	var le *LoadError
+	require.ErrorAs(t, err, &le)
+
+	var plugins []string
+	require.NoError(t, cfg.UnmarshalKey("plugins", &plugins))
+	assert.Equal(t, []string{"a"}, plugins)
+}
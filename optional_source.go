@@ -0,0 +1,65 @@
+package config
+
+import (
+	"context"
+	"fmt"
+)
+
+// SkipError 表示 Source 被跳过：例如可选的本地文件不存在。
+// DefaultConfig 遇到 SkipError 时会把该 Source 当作空层继续加载，并在 Layers() 中记录跳过原因。
+type SkipError struct {
+	Source string
+	Reason error
+}
+
+func (e *SkipError) Error() string {
+	return fmt.Sprintf("source %q skipped: %v", e.Source, e.Reason)
+}
+
+func (e *SkipError) Unwrap() error {
+	return e.Reason
+}
+
+// OptionalSource 是 Optional 返回的装饰器，内容不存在时不会导致 Load 失败。
+type OptionalSource struct {
+	src Source
+}
+
+var _ ContextSource = (*OptionalSource)(nil)
+
+// Optional 把 src 包装为可选 Source：当内容不存在时（本地文件不存在、HTTP 404、etcd key 不存在），
+// 视为一个空层而不是错误。权限不足、解析失败等其他错误仍然会让 Load 失败。
+//
+// 典型用途是只存在于开发机上的本地覆盖文件：
+//
+//	cfg.Load(
+//	    NewFileSource("config/base.yaml"),
+//	    Optional(NewFileSource("config/local.yaml")),
+//	)
+func Optional(src Source) *OptionalSource {
+	return &OptionalSource{src: src}
+}
+
+// Name 返回被包装 Source 的名称。
+func (o *OptionalSource) Name() string {
+	return sourceName(o.src, Metadata{})
+}
+
+// Unwrap 返回被包装的原始 Source。
+func (o *OptionalSource) Unwrap() Source {
+	return o.src
+}
+
+// Load 实现 Source 接口。
+func (o *OptionalSource) Load() ([]byte, Metadata, error) {
+	return o.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口，内容不存在时返回 *SkipError。
+func (o *OptionalSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	data, meta, err := loadSource(ctx, o.src)
+	if err != nil && isNotFound(err) {
+		return nil, meta, &SkipError{Source: sourceName(o.src, meta), Reason: err}
+	}
+	return data, meta, err
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestOptional(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing.json":
+			http.NotFound(w, r)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	newConfig := func() *DefaultConfig {
+		return NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}), WithDecoder(decoder.JSONDecoder{}))
+	}
+
+	t.Run("MissingIsSkipped", func(t *testing.T) {
+		cfg := newConfig()
+		err := cfg.Load(
+			NewFileSource("testdata/base.yaml"),
+			NewFileSource("testdata/local.yaml", WithFileSourceOptional()),
+			Optional(NewHTTPSource(srv.URL+"/missing.json")),
+		)
+		require.NoError(t, err)
+
+		layers := cfg.Layers()
+		require.Len(t, layers, 3)
+		assert.False(t, layers[0].Skipped)
+		assert.True(t, layers[1].Skipped)
+		assert.Equal(t, "testdata/local.yaml", layers[1].Name)
+		assert.True(t, layers[2].Skipped)
+		assert.Contains(t, layers[2].Reason, "404")
+	})
+
+	t.Run("OtherErrorsStillFail", func(t *testing.T) {
+		cfg := newConfig()
+		err := cfg.Load(Optional(NewHTTPSource(srv.URL + "/broken.json")))
+		var se *HTTPStatusError
+		require.ErrorAs(t, err, &se)
+		assert.Equal(t, http.StatusInternalServerError, se.StatusCode)
+
+		bad := filepath.Join(t.TempDir(), "bad.yaml")
+		require.NoError(t, os.WriteFile(bad, []byte("a: [\n"), 0o644))
+		err = cfg.Load(NewFileSource(bad, WithFileSourceOptional()))
+		assert.ErrorAs(t, err, new(*DecodeError))
+	})
+}
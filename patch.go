@@ -0,0 +1,579 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MergePatchStrategy 按 RFC 7386（JSON Merge Patch）合并：对象递归合并，null 删除对应 key，
+// 其他值（包括数组）整体替换。
+type MergePatchStrategy struct{}
+
+var _ MergeStrategy = MergePatchStrategy{}
+
+// Merge 实现 MergeStrategy 接口。
+func (MergePatchStrategy) Merge(dst, src map[string]any) (map[string]any, error) {
+	if dst == nil {
+		dst = make(map[string]any)
+	}
+	for k, v := range src {
+		if v == nil {
+			delete(dst, k)
+			continue
+		}
+		patch, ok := toStringMap(v)
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		target, ok := toStringMap(dst[k])
+		if !ok {
+			target = nil
+		}
+		merged, err := (MergePatchStrategy{}).Merge(target, patch)
+		if err != nil {
+			return nil, err
+		}
+		dst[k] = merged
+	}
+	return dst, nil
+}
+
+// JSONMergePatch 把 src 的内容作为 RFC 7386 Merge Patch 作用于层栈中它之前已合并的配置。
+// 内容可以是任意已注册 Decoder 支持的格式，YAML 中的 ~ / null 同样表示删除。
+func JSONMergePatch(src Source) *MergeStrategySource {
+	return WithSourceMergeStrategy(src, MergePatchStrategy{})
+}
+
+// JSONPatchSource 是 JSONPatch 返回的装饰器，其内容是 RFC 6902 操作列表。
+type JSONPatchSource struct {
+	src Source
+}
+
+var _ ContextSource = (*JSONPatchSource)(nil)
+
+// JSONPatch 把 src 的内容作为 RFC 6902（JSON Patch）操作列表，在层栈中它所在的位置依次作用于已合并的配置：
+//
+//	[
+//	  {"op": "replace", "path": "/server/port", "value": 9090},
+//	  {"op": "test",    "path": "/env", "value": "prod"},
+//	  {"op": "remove",  "path": "/debug"},
+//	  {"op": "add",     "path": "/plugins/-", "value": "audit"}
+//	]
+//
+// 支持 add / remove / replace / move / copy / test，路径为 JSON Pointer（RFC 6901）。
+// 任一操作失败（包括 test 不匹配）时整个 patch 不生效，错误为 *PatchError。
+func JSONPatch(src Source) *JSONPatchSource {
+	return &JSONPatchSource{src: src}
+}
+
+// Name 返回被包装 Source 的名称。
+func (p *JSONPatchSource) Name() string {
+	return sourceName(p.src, Metadata{})
+}
+
+// Unwrap 返回被包装的原始 Source。
+func (p *JSONPatchSource) Unwrap() Source {
+	return p.src
+}
+
+// Load 实现 Source 接口。
+func (p *JSONPatchSource) Load() ([]byte, Metadata, error) {
+	return p.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (p *JSONPatchSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	return loadSource(ctx, p.src)
+}
+
+// PatchOperation 是 RFC 6902 中的一个操作。
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// PatchError 表示 JSON Patch 中的某个操作失败。
+type PatchError struct {
+	// Index 是失败操作在列表中的下标（从 0 开始）
+	Index int
+	Op    PatchOperation
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("json patch operation #%d (%s %s) failed: %v", e.Index, e.Op.Op, e.Op.Path, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// ErrPatchTestFailed 表示 test 操作的值与当前配置不一致。
+var ErrPatchTestFailed = errors.New("test failed")
+
+// PatchTestError 描述 test 操作失败时的期望值与实际值，满足 errors.Is(err, ErrPatchTestFailed)。
+// 路径为敏感路径（见 WithSensitiveKeys）时，Expected 与 Actual 均为 RedactedValue。
+type PatchTestError struct {
+	Path     string
+	Expected any
+	Actual   any
+	// Missing 表示路径不存在
+	Missing bool
+}
+
+func (e *PatchTestError) Error() string {
+	if e.Missing {
+		return fmt.Sprintf("test failed: path %q does not exist, expected %s", e.Path, jsonString(e.Expected))
+	}
+	return fmt.Sprintf("test failed: path %q is %s, expected %s", e.Path, jsonString(e.Actual), jsonString(e.Expected))
+}
+
+// redact 把期望值与实际值替换为 RedactedValue，用于敏感路径上的 test 操作。
+func (e *PatchTestError) redact() {
+	e.Expected = RedactedValue
+	if !e.Missing {
+		e.Actual = RedactedValue
+	}
+}
+
+func (e *PatchTestError) Is(target error) bool {
+	return target == ErrPatchTestFailed
+}
+
+func jsonString(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// parseJSONPatch 解析 RFC 6902 操作列表。
+func parseJSONPatch(raw []byte) ([]PatchOperation, error) {
+	var ops []PatchOperation
+	if err := json.Unmarshal(raw, &ops); err != nil {
+		return nil, fmt.Errorf("parse json patch failed: %w", err)
+	}
+	// 区分缺失的 value 与 "value": null
+	var rawOps []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &rawOps); err != nil {
+		return nil, fmt.Errorf("parse json patch failed: %w", err)
+	}
+	for i, op := range ops {
+		switch op.Op {
+		case "add", "replace", "test":
+			if _, ok := rawOps[i]["value"]; !ok {
+				return nil, &PatchError{Index: i, Op: op, Err: errors.New(`missing "value"`)}
+			}
+		case "move", "copy":
+			if _, ok := rawOps[i]["from"]; !ok {
+				return nil, &PatchError{Index: i, Op: op, Err: errors.New(`missing "from"`)}
+			}
+		case "remove":
+		default:
+			return nil, &PatchError{Index: i, Op: op, Err: fmt.Errorf("unknown op %q", op.Op)}
+		}
+	}
+	return ops, nil
+}
+
+// applyJSONPatch 依次执行 ops，返回新的配置。doc 会被原地修改，调用方需要传入副本。
+func applyJSONPatch(doc map[string]any, ops []PatchOperation) (map[string]any, error) {
+	var root any = doc
+	for i, op := range ops {
+		var err error
+		root, err = applyPatchOperation(root, op)
+		if err != nil {
+			return nil, &PatchError{Index: i, Op: op, Err: err}
+		}
+	}
+	out, ok := toStringMap(root)
+	if !ok {
+		return nil, fmt.Errorf("json patch must keep the document an object, got %T", root)
+	}
+	return out, nil
+}
+
+func applyPatchOperation(root any, op PatchOperation) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add":
+		return pointerAdd(root, path, cloneValue(op.Value))
+	case "remove":
+		root, _, err = pointerRemove(root, path)
+		return root, err
+	case "replace":
+		if len(path) == 0 {
+			return cloneValue(op.Value), nil
+		}
+		if _, err := pointerGet(root, path); err != nil {
+			return nil, err
+		}
+		if root, _, err = pointerRemove(root, path); err != nil {
+			return nil, err
+		}
+		return pointerAdd(root, path, cloneValue(op.Value))
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" && len(path) > len(from) && pointerHasPrefix(path, from) {
+			return nil, fmt.Errorf("cannot move %q into its own child %q", op.From, op.Path)
+		}
+		v, err := pointerGet(root, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if root, _, err = pointerRemove(root, from); err != nil {
+				return nil, err
+			}
+		} else {
+			v = cloneValue(v)
+		}
+		return pointerAdd(root, path, v)
+	case "test":
+		v, err := pointerGet(root, path)
+		if err != nil {
+			return nil, &PatchTestError{Path: op.Path, Expected: op.Value, Missing: true}
+		}
+		if !jsonEqual(v, op.Value) {
+			return nil, &PatchTestError{Path: op.Path, Expected: op.Value, Actual: v}
+		}
+		return root, nil
+	default:
+		return nil, fmt.Errorf("unknown op %q", op.Op)
+	}
+}
+
+// parsePointer 解析 RFC 6901 JSON Pointer，"" 表示整个文档。
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("invalid json pointer %q: must start with '/'", p)
+	}
+	parts := strings.Split(p[1:], "/")
+	for i, s := range parts {
+		parts[i] = strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+	}
+	return parts, nil
+}
+
+func pointerHasPrefix(p, prefix []string) bool {
+	for i := range prefix {
+		if p[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// pointerPath 把 JSON Pointer 转换为配置路径（以 "." 连接），用于位置记录与路径检查。
+func pointerPath(p string) string {
+	parts, err := parsePointer(p)
+	if err != nil {
+		return ""
+	}
+	return strings.Join(parts, ".")
+}
+
+func pointerGet(root any, path []string) (any, error) {
+	cur := root
+	for i, tok := range path {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[tok]
+			if !ok {
+				return nil, fmt.Errorf("path %q does not exist", "/"+strings.Join(path[:i+1], "/"))
+			}
+			cur = v
+		case []any:
+			idx, err := arrayIndex(tok, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			cur = node[idx]
+		default:
+			return nil, fmt.Errorf("path %q does not exist", "/"+strings.Join(path[:i+1], "/"))
+		}
+	}
+	return cur, nil
+}
+
+// pointerAdd 在 path 处添加或替换值，返回新的根节点（数组插入会产生新的切片）。
+func pointerAdd(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := pointerGet(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+		return root, nil
+	case []any:
+		idx, err := arrayIndex(last, len(node), true)
+		if err != nil {
+			return nil, err
+		}
+		list := append(node[:idx:idx], append([]any{value}, node[idx:]...)...)
+		return setParent(root, path[:len(path)-1], list)
+	default:
+		return nil, fmt.Errorf("cannot add to %q: parent is not an object or array", "/"+strings.Join(path, "/"))
+	}
+}
+
+// pointerRemove 删除 path 处的值，返回新的根节点与被删除的值。
+func pointerRemove(root any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("cannot remove the whole document")
+	}
+	parent, err := pointerGet(root, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		v, ok := node[last]
+		if !ok {
+			return nil, nil, fmt.Errorf("path %q does not exist", "/"+strings.Join(path, "/"))
+		}
+		delete(node, last)
+		return root, v, nil
+	case []any:
+		idx, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		v := node[idx]
+		list := append(node[:idx:idx], node[idx+1:]...)
+		root, err = setParent(root, path[:len(path)-1], list)
+		return root, v, err
+	default:
+		return nil, nil, fmt.Errorf("path %q does not exist", "/"+strings.Join(path, "/"))
+	}
+}
+
+// setParent 把 path 处的值替换为 value（用于数组长度变化后回写）。
+func setParent(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := pointerGet(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+	case []any:
+		idx, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		node[idx] = value
+	}
+	return root, nil
+}
+
+// arrayIndex 解析数组下标，forAdd 为 true 时允许 "-" 与 len（追加到末尾）。
+func arrayIndex(tok string, n int, forAdd bool) (int, error) {
+	if tok == "-" {
+		if forAdd {
+			return n, nil
+		}
+		return 0, errors.New(`array index "-" is only valid for add`)
+	}
+	if tok == "" || (len(tok) > 1 && tok[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+	idx, err := strconv.Atoi(tok)
+	if err != nil || idx < 0 {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+	if idx > n || (!forAdd && idx == n) {
+		return 0, fmt.Errorf("array index %d out of range (len %d)", idx, n)
+	}
+	return idx, nil
+}
+
+// jsonEqual 按 JSON 语义比较两个值：数字按数值比较，不区分 int / float64。
+func jsonEqual(a, b any) bool {
+	na, okA := toFloat(a)
+	nb, okB := toFloat(b)
+	if okA && okB {
+		return na == nb
+	}
+	if m1, ok := toStringMap(a); ok {
+		m2, ok := toStringMap(b)
+		if !ok || len(m1) != len(m2) {
+			return false
+		}
+		for k, v := range m1 {
+			v2, ok := m2[k]
+			if !ok || !jsonEqual(v, v2) {
+				return false
+			}
+		}
+		return true
+	}
+	if l1, ok := a.([]any); ok {
+		l2, ok := b.([]any)
+		if !ok || len(l1) != len(l2) {
+			return false
+		}
+		for i := range l1 {
+			if !jsonEqual(l1[i], l2[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// applyPatchLayer 把 JSON Patch 层作用于 dst 的副本。KeyFilter / 可信级别不允许的操作被跳过并记录，
+// 锁定路径按 LockPolicy 处理；被修改的路径记录为来自该层。
+func (c *DefaultConfig) applyPatchLayer(dst map[string]any, src Source, name string, st *mergeState, raw []byte) (map[string]any, error) {
+	ops, err := parseJSONPatch(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if g := newKeyGuard(src, st.trust, dst); g != nil {
+		kept := ops[:0:0]
+		for _, op := range ops {
+			if op.Op == "test" {
+				kept = append(kept, op)
+				continue
+			}
+			// 所有写操作都会替换目标路径下已有的子树
+			reason := g.checkOverwrite(pointerPath(op.Path))
+			if reason == "" && op.Op == "move" {
+				reason = g.checkOverwrite(pointerPath(op.From))
+			}
+			if reason != "" {
+				st.rejected = append(st.rejected, RejectedKey{Path: pointerPath(op.Path), Source: name, Reason: reason})
+				c.logger.Warn("config key rejected", "path", pointerPath(op.Path), "source", name, "reason", reason)
+				continue
+			}
+			kept = append(kept, op)
+		}
+		ops = kept
+	}
+
+	locked := lockedPaths(dst, st.locks)
+	patched, err := applyJSONPatch(cloneMap(dst), ops)
+	if err != nil {
+		// 敏感路径上的 test 失败不能在错误中带出期望值与实际值
+		var te *PatchTestError
+		if errors.As(err, &te) && st.sensitive != nil && st.sensitive(pointerPath(te.Path)) {
+			te.redact()
+			var pe *PatchError
+			if errors.As(err, &pe) {
+				pe.Op.Value = RedactedValue
+			}
+		}
+		return nil, err
+	}
+	var restored []string
+	if len(locked) > 0 {
+		if restored, err = c.enforceLocks(dst, patched, locked, name, st); err != nil {
+			return nil, err
+		}
+	}
+
+	kept := savePositions(st.positions, restored)
+	for _, op := range ops {
+		// 只清除被 remove / move 删除的路径；flat key（例如 .properties 中的 "db.host"）不受影响
+		var removed string
+		switch op.Op {
+		case "remove":
+			removed = pointerPath(op.Path)
+		case "move":
+			removed = pointerPath(op.From)
+		}
+		if removed != "" {
+			if _, ok := getByPath(patched, removed); !ok {
+				deletePositions(st.positions, []string{removed})
+			}
+		}
+
+		switch op.Op {
+		case "add", "replace", "move", "copy":
+			p := mapPathPrefix(patched, op.Path)
+			if p == "" {
+				continue
+			}
+			deletePositions(st.positions, []string{p})
+			st.positions[p] = Position{Source: name}
+			if v, ok := getByPath(patched, p); ok {
+				if m, ok := toStringMap(v); ok {
+					recordPositions(st.positions, p, m, name, nil)
+				}
+			}
+		}
+	}
+	deletePositions(st.positions, restored)
+	for k, v := range kept {
+		st.positions[k] = v
+	}
+	return patched, nil
+}
+
+// mapPathPrefix 返回 JSON Pointer 在 doc 中经过的最长 map 路径，遇到数组时截断到数组本身。
+func mapPathPrefix(doc map[string]any, pointer string) string {
+	parts, err := parsePointer(pointer)
+	if err != nil {
+		return ""
+	}
+	var cur any = doc
+	var out []string
+	for _, tok := range parts {
+		m, ok := toStringMap(cur)
+		if !ok {
+			break
+		}
+		out = append(out, tok)
+		if cur, ok = m[tok]; !ok {
+			break
+		}
+	}
+	return strings.Join(out, ".")
+}
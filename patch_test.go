@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+const patchBase = `{
+	"env": "prod",
+	"server": {"port": 80, "host": "0.0.0.0"},
+	"debug": true,
+	"plugins": ["auth", "metrics"],
+	"a~b": {"c/d": 1}
+}`
+
+func TestJSONPatch(t *testing.T) {
+	patch := &switchSource{data: `[
+		{"op": "test", "path": "/env", "value": "prod"},
+		{"op": "replace", "path": "/server/port", "value": 9090},
+		{"op": "remove", "path": "/debug"},
+		{"op": "add", "path": "/plugins/-", "value": "audit"},
+		{"op": "add", "path": "/plugins/0", "value": "trace"},
+		{"op": "add", "path": "/admin", "value": {}},
+		{"op": "copy", "from": "/server/host", "path": "/admin/host"},
+		{"op": "move", "from": "/a~0b/c~1d", "path": "/moved"},
+		{"op": "test", "path": "/moved", "value": 1.0}
+	]`}
+
+	cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+	require.NoError(t, cfg.Load(&switchSource{data: patchBase}, Prioritized("patch", PriorityRemote, JSONPatch(patch))))
+
+	var got map[string]any
+	require.NoError(t, cfg.Unmarshal(&got))
+	assert.Equal(t, map[string]any{
+		"env":     "prod",
+		"server":  map[string]any{"port": float64(9090), "host": "0.0.0.0"},
+		"plugins": []any{"trace", "auth", "metrics", "audit"},
+		"admin":   map[string]any{"host": "0.0.0.0"},
+		"a~b":     map[string]any{},
+		"moved":   float64(1),
+	}, got)
+
+	pos, _ := cfg.Position("server.port")
+	assert.Equal(t, "patch", pos.Source)
+	_, ok := cfg.Position("debug")
+	assert.False(t, ok)
+}
+
+func TestJSONPatch_TestFailed(t *testing.T) {
+	patch := &switchSource{data: `[
+		{"op": "replace", "path": "/server/port", "value": 9090},
+		{"op": "test", "path": "/env", "value": "staging"}
+	]`}
+	cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+	err := cfg.Load(&switchSource{data: patchBase}, JSONPatch(patch))
+
+	assert.ErrorIs(t, err, ErrPatchTestFailed)
+	var pe *PatchError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, 1, pe.Index)
+	assert.Contains(t, err.Error(), `json patch operation #1 (test /env) failed: test failed: path "/env" is "prod", expected "staging"`)
+
+	// 敏感路径上的 test 失败不泄露期望值与实际值
+	secret := &switchSource{data: `[{"op": "test", "path": "/db/password", "value": "guess"}]`}
+	cfg.MarkSensitive("db.password")
+	err = cfg.Load(&switchSource{data: `{"db":{"password":"s3cr3t"}}`}, JSONPatch(secret))
+	require.ErrorIs(t, err, ErrPatchTestFailed)
+	assert.Contains(t, err.Error(), `path "/db/password" is "******", expected "******"`)
+	assert.NotContains(t, err.Error(), "s3cr3t")
+	assert.NotContains(t, err.Error(), "guess")
+	var te *PatchTestError
+	require.ErrorAs(t, err, &te)
+	assert.Equal(t, RedactedValue, te.Actual)
+
+	for _, bad := range []string{
+		`[{"op": "remove", "path": "/missing"}]`,
+		`[{"op": "add", "path": "/plugins/5", "value": 1}]`,
+		`[{"op": "add", "path": "server"}]`,
+		`[{"op": "jump", "path": "/a"}]`,
+		`{"op": "add"}`,
+	} {
+		err := cfg.Load(&switchSource{data: patchBase}, JSONPatch(&switchSource{data: bad}))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestJSONMergePatch(t *testing.T) {
+	cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+	require.NoError(t, cfg.Load(
+		&switchSource{data: patchBase},
+		JSONMergePatch(&switchSource{data: `{"server": {"port": 8080, "host": null}, "debug": null, "plugins": ["x"]}`}),
+	))
+
+	var got map[string]any
+	require.NoError(t, cfg.Unmarshal(&got))
+	assert.Equal(t, map[string]any{"port": float64(8080)}, got["server"])
+	assert.Equal(t, []any{"x"}, got["plugins"])
+	assert.NotContains(t, got, "debug")
+	_, ok := cfg.Position("server.host")
+	assert.False(t, ok)
+}
+
+func TestJSONPatch_KeepsFlatKeyPositions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.properties")
+	require.NoError(t, os.WriteFile(path, []byte("db.host=127.0.0.1\ndebug=true\n"), 0o600))
+
+	cfg := NewDefaultConfig(WithDecoder(decoder.PropertiesDecoder{}), WithDecoder(decoder.JSONDecoder{}))
+	require.NoError(t, cfg.Load(
+		NewFileSource(path),
+		JSONPatch(&switchSource{data: `[{"op": "remove", "path": "/debug"}]`}),
+	))
+
+	pos, ok := cfg.Position("db.host")
+	require.True(t, ok)
+	assert.Equal(t, Position{Source: path, Line: 1, Column: 1}, pos)
+	_, ok = cfg.Position("debug")
+	assert.False(t, ok)
+}
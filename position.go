@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+// Position 描述某个配置值来自哪个 Source，以及在该 Source 中的行列号。
+// 当 Decoder 无法提供位置信息时，Line/Column 为 0，此时只有 Source 有意义。
+type Position struct {
+	Source string
+	Line   int
+	Column int
+}
+
+// String 返回 "base.yaml:12:3" 形式的位置描述。
+func (p Position) String() string {
+	switch {
+	case p.Line <= 0:
+		return p.Source
+	case p.Column <= 0:
+		return fmt.Sprintf("%s:%d", p.Source, p.Line)
+	default:
+		return fmt.Sprintf("%s:%d:%d", p.Source, p.Line, p.Column)
+	}
+}
+
+// recordPositions 遍历一个 Source 解析出的 map，为每个路径记录来源位置。
+// 后加载的 Source 会覆盖先前记录的同名路径，与合并时的覆盖关系保持一致。
+func recordPositions(dst map[string]Position, prefix string, m map[string]any, source string, positions decoder.Positions) {
+	for k, v := range m {
+		path := joinPath(prefix, k)
+		pos := Position{Source: source}
+		if p, ok := positions[path]; ok {
+			pos.Line, pos.Column = p.Line, p.Column
+		}
+		dst[path] = pos
+
+		sub, ok := toStringMap(v)
+		if !ok {
+			// 非 map 值会整体覆盖先前的子树，先前子路径的位置随之失效
+			for p := range dst {
+				if strings.HasPrefix(p, path+".") {
+					delete(dst, p)
+				}
+			}
+			continue
+		}
+		recordPositions(dst, path, sub, source, positions)
+	}
+}
+
+// removedNulls 返回 m 中值为 null、且合并后在 merged 中已不存在的路径。
+// 例如 JSONMergePatch 用 null 删除 key 时，recordPositions 记录下的位置需要随之清除。
+func removedNulls(prefix string, m, merged map[string]any) []string {
+	var out []string
+	for k, v := range m {
+		path := joinPath(prefix, k)
+		if v == nil {
+			if _, ok := getByPath(merged, path); !ok {
+				out = append(out, path)
+			}
+			continue
+		}
+		if sub, ok := toStringMap(v); ok {
+			out = append(out, removedNulls(path, sub, merged)...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"context"
+	"sort"
+)
+
+// 标准优先级槽位。数值越大越晚合并，即优先级越高：
+//
+//	defaults < files < remote < env < flags < overrides
+//
+// 槽位之间留有间隔，可以用 PriorityFiles+10 这样的值插入自定义层。
+const (
+	PriorityDefaults  = 0
+	PriorityFiles     = 100
+	PriorityRemote    = 200
+	PriorityEnv       = 300
+	PriorityFlags     = 400
+	PriorityOverrides = 500
+)
+
+// defaultPriority 是未显式指定优先级的 Source 的优先级。
+// 同一优先级内按注册/传参顺序合并，因此不使用优先级时行为与旧版本完全一致。
+const defaultPriority = PriorityFiles
+
+// PrioritizedSource 为 Source 附加名称与优先级，由 Prioritized 创建。
+type PrioritizedSource struct {
+	src      Source
+	name     string
+	priority int
+}
+
+var _ ContextSource = (*PrioritizedSource)(nil)
+
+// Prioritized 为 src 指定层名称与优先级。DefaultConfig 按优先级从低到高合并，
+// 不再依赖 Load 参数的顺序，适合多个包各自贡献 Source 的场景：
+//
+//	cfg.Load(
+//	    Prioritized("env", PriorityEnv, NewEnvSource(WithEnvSourcePrefix("APP_"))),
+//	    Prioritized("base", PriorityFiles, NewFileSource("config/base.yaml")),
+//	)
+//
+// name 非空时会替代原始 Source 名称，出现在 Layers()、Position() 和错误信息中。
+func Prioritized(name string, priority int, src Source) *PrioritizedSource {
+	return &PrioritizedSource{src: src, name: name, priority: priority}
+}
+
+// Name 返回层名称，未指定时返回原始 Source 的名称。
+func (p *PrioritizedSource) Name() string {
+	if p.name != "" {
+		return p.name
+	}
+	return sourceName(p.src, Metadata{})
+}
+
+// Priority 返回层优先级。
+func (p *PrioritizedSource) Priority() int {
+	return p.priority
+}
+
+// Unwrap 返回被包装的原始 Source。
+func (p *PrioritizedSource) Unwrap() Source {
+	return p.src
+}
+
+// Load 实现 Source 接口。
+func (p *PrioritizedSource) Load() ([]byte, Metadata, error) {
+	return p.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口，并用层名称替换 Metadata.Source。
+func (p *PrioritizedSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	data, meta, err := loadSource(ctx, p.src)
+	if p.name != "" {
+		meta.Source = p.name
+	}
+	return data, meta, err
+}
+
+// WithSource 在构造 DefaultConfig 时注册一个带名称与优先级的 Source，等价于 AddSource。
+func WithSource(name string, priority int, src Source) Option {
+	return func(c *DefaultConfig) {
+		if src != nil {
+			c.sources = append(c.sources, Prioritized(name, priority, src))
+		}
+	}
+}
+
+// AddSource 注册一个带名称与优先级的 Source。
+// 注册的 Source 会参与之后每一次 Load，与 Load 参数中的 Source 一起按优先级合并；
+// 因此注册完成后可以直接调用 cfg.Load() 加载全部层。
+func (c *DefaultConfig) AddSource(name string, priority int, src Source) {
+	if src == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sources = append(c.sources, Prioritized(name, priority, src))
+}
+
+// sourcePriority 返回 src（或其包装链上）声明的优先级，未声明时为 defaultPriority。
+func sourcePriority(src Source) int {
+	if p, ok := findSource[interface{ Priority() int }](src); ok {
+		return p.Priority()
+	}
+	return defaultPriority
+}
+
+// stackEntry 是层栈中的一项。
+type stackEntry struct {
+	src      Source
+	priority int
+}
+
+// layerStack 合并已注册的 Source 与本次传入的 Source，并按优先级稳定排序。
+// 优先级相同时，注册的 Source 在前，随后是传入的 Source，各自保持原有顺序。
+func (c *DefaultConfig) layerStack(sources []Source) []stackEntry {
+	c.mu.RLock()
+	all := make([]Source, 0, len(c.sources)+len(sources))
+	all = append(all, c.sources...)
+	c.mu.RUnlock()
+	all = append(all, sources...)
+
+	stack := make([]stackEntry, 0, len(all))
+	for _, src := range all {
+		if src == nil {
+			continue
+		}
+		stack = append(stack, stackEntry{src: src, priority: sourcePriority(src)})
+	}
+	sort.SliceStable(stack, func(i, j int) bool {
+		return stack[i].priority < stack[j].priority
+	})
+	return stack
+}
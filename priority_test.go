@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func fakeEnvSource(name string, env ...string) *EnvSource {
+	return NewEnvSource(
+		WithEnvSourceName(name),
+		WithEnvSourceEnviron(func() []string { return env }),
+	)
+}
+
+func TestDefaultConfig_Priority(t *testing.T) {
+	cfg := NewDefaultConfig(
+		WithDecoder(decoder.YAMLDecoder{}),
+		WithDecoder(decoder.JSONDecoder{}),
+		WithSource("env", PriorityEnv, fakeEnvSource("raw-env", "SERVER__PORT=7000")),
+	)
+	cfg.AddSource("defaults", PriorityDefaults, fakeEnvSource("raw-defaults", "SERVER__PORT=1", "SERVER__NAME=demo"))
+
+	// 参数顺序与优先级相反，最终按优先级合并
+	require.NoError(t, cfg.Load(
+		Prioritized("remote", PriorityRemote, fakeEnvSource("raw-remote", "SERVER__PORT=6000")),
+		NewFileSource("testdata/base.yaml"),
+	))
+
+	port, _ := cfg.GetInt("server.port")
+	assert.Equal(t, 7000, port)
+	name, _ := cfg.GetString("server.name")
+	assert.Equal(t, "demo", name)
+
+	var stack []string
+	for _, l := range cfg.Layers() {
+		stack = append(stack, l.Name)
+	}
+	assert.Equal(t, []string{"defaults", "testdata/base.yaml", "remote", "env"}, stack)
+
+	pos, _ := cfg.Position("server.port")
+	assert.Equal(t, "env", pos.Source)
+
+	// 注册的 Source 在无参 Load 时同样生效
+	require.NoError(t, cfg.Load())
+	assert.Len(t, cfg.Layers(), 2)
+}
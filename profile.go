@@ -0,0 +1,256 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// 激活 profile 的默认来源。
+const (
+	// DefaultProfilesEnv 是默认读取激活 profile 的环境变量，多个 profile 以逗号分隔
+	DefaultProfilesEnv = "CONFIG_PROFILES_ACTIVE"
+	// DefaultProfilesKey 是默认读取激活 profile 的配置路径，值可以是字符串（逗号分隔）或字符串列表
+	DefaultProfilesKey = "profiles.active"
+)
+
+// WithProfiles 开启 profile 支持并指定默认激活的 profile。对于层栈中的每个 FileSource（例如 config/app.yaml），
+// Load 时会在其后依次追加同目录下的 app-<profile>.yaml 作为更晚合并的层，文件不存在时跳过：
+//
+//	cfg := NewDefaultConfig(WithProfiles("prod", "cn-north"))
+//	// 依次合并 config/app.yaml、config/app-prod.yaml、config/app-cn-north.yaml
+//	_ = cfg.Load(NewFileSource("config/app.yaml"))
+//
+// 激活的 profile 按以下顺序确定，先找到者生效：
+//  1. 环境变量 CONFIG_PROFILES_ACTIVE（见 WithProfilesEnv）
+//  2. WithProfiles 指定的 profile
+//  3. 各 FileSource 中的 profiles.active（见 WithProfilesKey），后合并的文件优先
+//
+// profile 层与原文件使用相同的优先级，紧跟在原文件之后合并；profile 文件中的 profiles.active 不会再次触发展开。
+// 解析结果记录在 LoadReport.Profiles 中，profile 层的 LayerInfo.Profile 为对应的 profile。
+func WithProfiles(profiles ...string) Option {
+	return func(c *DefaultConfig) {
+		c.profilesEnabled = true
+		c.profiles = normalizeProfiles(profiles)
+	}
+}
+
+// WithProfilesEnv 开启 profile 支持，并指定读取激活 profile 的环境变量，为空表示不从环境变量读取。
+func WithProfilesEnv(name string) Option {
+	return func(c *DefaultConfig) {
+		c.profilesEnabled = true
+		c.profilesEnv = strings.TrimSpace(name)
+	}
+}
+
+// WithProfilesKey 开启 profile 支持，并指定基础文件中声明激活 profile 的配置路径，为空表示不从文件读取。
+func WithProfilesKey(key string) Option {
+	return func(c *DefaultConfig) {
+		c.profilesEnabled = true
+		c.profilesKey = strings.TrimSpace(key)
+	}
+}
+
+// ProfileResolution 描述一次 Load 中激活 profile 的解析结果。
+type ProfileResolution struct {
+	// Active 是激活的 profile，按合并顺序排列
+	Active []string
+	// From 是 Active 的来源："env:<变量名>"、"option"，或 "<Source 名称>:<配置路径>"；没有激活 profile 时为空
+	From string
+}
+
+// profileSource 是 profile 展开生成的层，包装同目录下的 <name>-<profile>.<ext> 文件。
+// parent 是基础文件所在的层（含其装饰器），profile 层因此继承基础文件的 KeyFilter、可信级别、锁定、
+// 敏感标记、Required 与合并策略。
+type profileSource struct {
+	src      *FileSource
+	parent   Source
+	profile  string
+	priority int
+}
+
+var _ ContextSource = (*profileSource)(nil)
+
+// Profile 返回该层对应的 profile。
+func (p *profileSource) Profile() string {
+	return p.profile
+}
+
+// Priority 返回原文件所在层的优先级。
+func (p *profileSource) Priority() int {
+	return p.priority
+}
+
+// Name 返回 profile 文件的名称。
+func (p *profileSource) Name() string {
+	return p.src.Name()
+}
+
+// Unwrap 返回 profile 文件对应的 FileSource。
+func (p *profileSource) Unwrap() Source {
+	return p.src
+}
+
+// Parent 返回基础文件所在的层，见 findSource。
+func (p *profileSource) Parent() Source {
+	return p.parent
+}
+
+// Load 实现 Source 接口。
+func (p *profileSource) Load() ([]byte, Metadata, error) {
+	return p.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (p *profileSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	return p.src.LoadContext(ctx)
+}
+
+// profileFile 返回 f 对应 profile 的可选 FileSource，例如 config/app.yaml => config/app-prod.yaml。
+func profileFile(f *FileSource, profile string) *FileSource {
+	ext := filepath.Ext(f.path)
+	if f.fsys != nil {
+		ext = path.Ext(f.path)
+	}
+	p := strings.TrimSuffix(f.path, ext) + "-" + profile + ext
+
+	name := p
+	if f.name != f.path {
+		name = f.name + "-" + profile
+	}
+	return &FileSource{path: p, format: f.format, fsys: f.fsys, name: name, optional: true}
+}
+
+// profileBase 返回 entry 对应的基础文件；profile 层、LayeredSource 展开出的文件与 JSONPatch 层不展开 profile。
+func profileBase(entry stackEntry) (*FileSource, bool) {
+	if _, ok := entry.src.(*layerPart); ok || sourceProfile(entry.src) != "" {
+		return nil, false
+	}
+	if _, ok := findSource[*JSONPatchSource](entry.src); ok {
+		return nil, false
+	}
+	return findSource[*FileSource](entry.src)
+}
+
+// sourceProfile 返回 src 所属的 profile，非 profile 层返回空字符串。
+func sourceProfile(src Source) string {
+	if p, ok := findSource[interface{ Profile() string }](src); ok {
+		return p.Profile()
+	}
+	return ""
+}
+
+// resolveProfiles 确定本次 Load 激活的 profile。fetched 与 stack 一一对应，用于读取基础文件中的声明。
+func (c *DefaultConfig) resolveProfiles(stack []stackEntry, fetched []fetchResult) ProfileResolution {
+	if c.profilesEnv != "" {
+		if v, ok := os.LookupEnv(c.profilesEnv); ok {
+			if active := normalizeProfiles(strings.Split(v, ",")); len(active) > 0 {
+				return ProfileResolution{Active: active, From: "env:" + c.profilesEnv}
+			}
+		}
+	}
+	if len(c.profiles) > 0 {
+		return ProfileResolution{Active: append([]string(nil), c.profiles...), From: "option"}
+	}
+	if c.profilesKey == "" {
+		return ProfileResolution{}
+	}
+
+	var res ProfileResolution
+	for i, entry := range stack {
+		if _, ok := findSource[*FileSource](entry.src); !ok || fetched[i].err != nil {
+			continue
+		}
+		if _, ok := findSource[*JSONPatchSource](entry.src); ok {
+			continue
+		}
+		name := sourceName(entry.src, fetched[i].meta)
+		// 解析失败留给合并阶段报告
+		layer, err := c.decode(fetched[i].data, fetched[i].meta.Format, name)
+		if err != nil {
+			continue
+		}
+		v, ok := getByPath(layer.data, c.profilesKey)
+		if !ok {
+			continue
+		}
+		if active := profileList(v); len(active) > 0 {
+			res = ProfileResolution{Active: active, From: name + ":" + c.profilesKey}
+		}
+	}
+	return res
+}
+
+// expandProfiles 在每个 FileSource 之后插入其 profile 层，并拉取新增层的内容。
+func (c *DefaultConfig) expandProfiles(ctx context.Context, stack []stackEntry, fetched []fetchResult, active []string) ([]stackEntry, []fetchResult) {
+	if len(active) == 0 {
+		return stack, fetched
+	}
+
+	var extra []stackEntry
+	for _, entry := range stack {
+		f, ok := profileBase(entry)
+		if !ok {
+			continue
+		}
+		for _, p := range active {
+			ps := &profileSource{src: profileFile(f, p), parent: entry.src, profile: p, priority: entry.priority}
+			extra = append(extra, stackEntry{src: ps, priority: entry.priority})
+		}
+	}
+	if len(extra) == 0 {
+		return stack, fetched
+	}
+	extraFetched := c.fetchAll(ctx, extra)
+
+	outStack := make([]stackEntry, 0, len(stack)+len(extra))
+	outFetched := make([]fetchResult, 0, len(stack)+len(extra))
+	next := 0
+	for i, entry := range stack {
+		outStack = append(outStack, entry)
+		outFetched = append(outFetched, fetched[i])
+		if _, ok := profileBase(entry); !ok {
+			continue
+		}
+		outStack = append(outStack, extra[next:next+len(active)]...)
+		outFetched = append(outFetched, extraFetched[next:next+len(active)]...)
+		next += len(active)
+	}
+	return outStack, outFetched
+}
+
+// profileList 把配置中声明的 profile（逗号分隔的字符串或字符串列表）转换为列表。
+func profileList(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return normalizeProfiles(strings.Split(t, ","))
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			out = append(out, fmt.Sprint(item))
+		}
+		return normalizeProfiles(out)
+	}
+	return nil
+}
+
+// normalizeProfiles 去掉空白与重复的 profile，保持原有顺序。
+func normalizeProfiles(profiles []string) []string {
+	var out []string
+	seen := make(map[string]struct{}, len(profiles))
+	for _, p := range profiles {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		out = append(out, p)
+	}
+	return out
+}
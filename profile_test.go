@@ -0,0 +1,111 @@
+package config
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func profileFS() fstest.MapFS {
+	return fstest.MapFS{
+		"config/app.yaml":          {Data: []byte("profiles:\n  active: [staging]\nenv: dev\nregion: local\nport: 80\n")},
+		"config/app-prod.yaml":     {Data: []byte("env: prod\nport: 443\n")},
+		"config/app-cn-north.yaml": {Data: []byte("region: cn-north\n")},
+		"config/app-staging.yaml":  {Data: []byte("env: staging\n")},
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	t.Run("option", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}), WithDecoder(decoder.JSONDecoder{}), WithProfiles("prod", "missing", "cn-north"))
+		require.NoError(t, cfg.Load(
+			NewFileSource("config/app.yaml", WithFileSourceFS(profileFS())),
+			&switchSource{data: `{"port": 8443}`},
+		))
+
+		env, _ := cfg.GetString("env")
+		region, _ := cfg.GetString("region")
+		port, _ := cfg.GetInt("port")
+		assert.Equal(t, "prod", env)
+		assert.Equal(t, "cn-north", region)
+		assert.Equal(t, 8443, port, "profile layers sit right after their base file")
+
+		pos, _ := cfg.Position("region")
+		assert.Equal(t, "config/app-cn-north.yaml", pos.Source)
+
+		layers := cfg.Layers()
+		require.Len(t, layers, 5)
+		assert.Equal(t, []string{"config/app.yaml", "config/app-prod.yaml", "config/app-missing.yaml", "config/app-cn-north.yaml", "remote"},
+			[]string{layers[0].Name, layers[1].Name, layers[2].Name, layers[3].Name, layers[4].Name})
+		assert.Equal(t, "prod", layers[1].Profile)
+		assert.True(t, layers[2].Skipped)
+		assert.Empty(t, layers[0].Profile)
+
+		assert.Equal(t, ProfileResolution{Active: []string{"prod", "missing", "cn-north"}, From: "option"}, cfg.LoadReport().Profiles)
+	})
+
+	t.Run("env wins over option", func(t *testing.T) {
+		t.Setenv(DefaultProfilesEnv, " cn-north , prod")
+		cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}), WithProfiles("staging"))
+		require.NoError(t, cfg.Load(NewFileSource("config/app.yaml", WithFileSourceFS(profileFS()))))
+
+		env, _ := cfg.GetString("env")
+		assert.Equal(t, "prod", env)
+		assert.Equal(t, ProfileResolution{Active: []string{"cn-north", "prod"}, From: "env:" + DefaultProfilesEnv}, cfg.LoadReport().Profiles)
+	})
+
+	t.Run("key in base file", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}), WithProfilesEnv(""))
+		require.NoError(t, cfg.Load(NewFileSource("config/app.yaml", WithFileSourceFS(profileFS()), WithFileSourceName("base"))))
+
+		env, _ := cfg.GetString("env")
+		assert.Equal(t, "staging", env)
+		pos, _ := cfg.Position("env")
+		assert.Equal(t, "base-staging", pos.Source)
+		assert.Equal(t, ProfileResolution{Active: []string{"staging"}, From: "base:profiles.active"}, cfg.LoadReport().Profiles)
+	})
+
+	t.Run("inherits base decorators", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}), WithProfiles("prod"))
+		base := WithKeyFilter(Sensitive(NewFileSource("config/app.yaml", WithFileSourceFS(profileFS()))), KeyFilter{Deny: []string{"port"}})
+		require.NoError(t, cfg.Load(base))
+
+		env, _ := cfg.GetString("env")
+		assert.Equal(t, "prod", env)
+		_, ok := cfg.Get("port")
+		assert.False(t, ok)
+		assert.True(t, cfg.IsSensitive("env"))
+		assert.Equal(t, []RejectedKey{
+			{Path: "port", Source: "config/app.yaml", Reason: `denied by "port"`},
+			{Path: "port", Source: "config/app-prod.yaml", Reason: `denied by "port"`},
+		}, cfg.LoadReport().RejectedKeys)
+	})
+
+	t.Run("json patch is not expanded", func(t *testing.T) {
+		fsys := profileFS()
+		fsys["config/patch.json"] = &fstest.MapFile{Data: []byte(`[{"op": "replace", "path": "/port", "value": 8080}]`)}
+		fsys["config/patch-prod.json"] = &fstest.MapFile{Data: []byte(`{"port": 1}`)}
+		cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}), WithDecoder(decoder.JSONDecoder{}), WithProfiles("prod"))
+		require.NoError(t, cfg.Load(
+			NewFileSource("config/app.yaml", WithFileSourceFS(fsys)),
+			JSONPatch(NewFileSource("config/patch.json", WithFileSourceFS(fsys))),
+		))
+
+		port, _ := cfg.GetInt("port")
+		assert.Equal(t, 8080, port)
+		assert.Len(t, cfg.Layers(), 3)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}))
+		require.NoError(t, cfg.Load(NewFileSource("config/app.yaml", WithFileSourceFS(profileFS()))))
+
+		env, _ := cfg.GetString("env")
+		assert.Equal(t, "dev", env)
+		assert.Len(t, cfg.Layers(), 1)
+	})
+}
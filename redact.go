@@ -0,0 +1,252 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RedactedValue 是敏感值在导出、调试输出和错误信息中的替代文本。
+const RedactedValue = "******"
+
+// WithSensitiveKeys 声明敏感路径，pattern 语法见 matchPathPattern：
+//
+//	WithSensitiveKeys("*.password", "**.token", "db.dsn")
+//
+// 匹配到的路径（及其子路径）在 Redacted() 等输出中会被替换为 RedactedValue。
+func WithSensitiveKeys(patterns ...string) Option {
+	return func(c *DefaultConfig) {
+		c.addSensitivePatterns(patterns...)
+	}
+}
+
+// MarkSensitive 在运行期追加敏感路径 pattern。
+func (c *DefaultConfig) MarkSensitive(patterns ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addSensitivePatterns(patterns...)
+}
+
+// MarkSensitiveStruct 扫描结构体类型中带有 `secret:"true"` 标签的字段，
+// 将其对应的配置路径标记为敏感。路径按 json 标签推导，与 Unmarshal 的映射规则一致。
+//
+//	type DB struct {
+//	    Host     string `json:"host"`
+//	    Password string `json:"password" secret:"true"`
+//	}
+//
+// Unmarshal / UnmarshalKey 会自动对目标结构体执行该扫描。
+func (c *DefaultConfig) MarkSensitiveStruct(v any) {
+	c.markSensitiveStruct("", v)
+}
+
+func (c *DefaultConfig) markSensitiveStruct(prefix string, v any) {
+	var paths []string
+	collectSecretTags(reflect.TypeOf(v), prefix, &paths, make(map[reflect.Type]bool))
+	if len(paths) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addSensitivePatterns(paths...)
+}
+
+// addSensitivePatterns 去重追加 pattern，调用方需持有写锁（或处于构造阶段）。
+func (c *DefaultConfig) addSensitivePatterns(patterns ...string) {
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		exists := false
+		for _, old := range c.sensitive {
+			if old == p {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			c.sensitive = append(c.sensitive, p)
+		}
+	}
+}
+
+// IsSensitive 判断 path 是否被标记为敏感。
+// path 自身或任一祖先路径命中 pattern，或在 Source 中被显式标记（如 YAML 的 !secret），均视为敏感。
+func (c *DefaultConfig) IsSensitive(path string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.isSensitive(path)
+}
+
+// isSensitive 是 IsSensitive 的无锁版本，调用方需持有锁。
+func (c *DefaultConfig) isSensitive(path string) bool {
+	if path == "" {
+		return false
+	}
+	return isSensitivePath(path, c.sensitive, c.secretPaths)
+}
+
+// isSensitivePath 依次检查 path 及其所有祖先路径。
+func isSensitivePath(path string, patterns []string, marked map[string]struct{}) bool {
+	for p := path; p != ""; {
+		if _, ok := marked[p]; ok {
+			return true
+		}
+		for _, pattern := range patterns {
+			if matchPathPattern(pattern, p) {
+				return true
+			}
+		}
+		i := strings.LastIndex(p, ".")
+		if i < 0 {
+			break
+		}
+		p = p[:i]
+	}
+	return false
+}
+
+// SensitiveSource 是 Sensitive 返回的装饰器，其设置的所有值都视为敏感。
+type SensitiveSource struct {
+	src Source
+}
+
+var _ ContextSource = (*SensitiveSource)(nil)
+
+// Sensitive 把 src 设置的所有路径标记为敏感，适用于整体都是密钥的 Source，例如挂载的 Kubernetes Secret：
+//
+//	cfg.Load(Sensitive(NewKubeVolumeSource("/etc/secrets", WithKubeVolumeKeyPrefix("secrets"))))
+func Sensitive(src Source) *SensitiveSource {
+	return &SensitiveSource{src: src}
+}
+
+// Sensitive 返回 true。
+func (s *SensitiveSource) Sensitive() bool {
+	return true
+}
+
+// Name 返回被包装 Source 的名称。
+func (s *SensitiveSource) Name() string {
+	return sourceName(s.src, Metadata{})
+}
+
+// Unwrap 返回被包装的原始 Source。
+func (s *SensitiveSource) Unwrap() Source {
+	return s.src
+}
+
+// Load 实现 Source 接口。
+func (s *SensitiveSource) Load() ([]byte, Metadata, error) {
+	return s.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (s *SensitiveSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	return loadSource(ctx, s.src)
+}
+
+// sourceSensitive 判断 src 设置的值是否整体视为敏感。
+func sourceSensitive(src Source) bool {
+	if s, ok := findSource[interface{ Sensitive() bool }](src); ok {
+		return s.Sensitive()
+	}
+	return false
+}
+
+// Redacted 返回当前配置的深拷贝，其中所有敏感值被替换为 RedactedValue。
+// 适合直接用于日志输出或调试展示：
+//
+//	log.Printf("effective config: %v", cfg.Redacted())
+func (c *DefaultConfig) Redacted() map[string]any {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.redactMap("", c.data)
+}
+
+// redactMap 深拷贝 m，并将敏感路径的值替换为 RedactedValue。调用方需持有锁。
+func (c *DefaultConfig) redactMap(prefix string, m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = c.redactValue(joinPath(prefix, k), v)
+	}
+	return out
+}
+
+func (c *DefaultConfig) redactValue(path string, v any) any {
+	if c.isSensitive(path) {
+		return RedactedValue
+	}
+	if sub, ok := toStringMap(v); ok {
+		return c.redactMap(path, sub)
+	}
+	if list, ok := v.([]any); ok {
+		cp := make([]any, len(list))
+		for i, item := range list {
+			if sub, ok := toStringMap(item); ok {
+				cp[i] = c.redactMap(path, sub)
+			} else {
+				cp[i] = item
+			}
+		}
+		return cp
+	}
+	return v
+}
+
+// collectSecretTags 递归收集带 `secret:"true"` 标签字段的配置路径。
+// map 类型的字段会以 "*" 作为 key 段，例如 map[string]DB => "dbs.*.password"。
+func collectSecretTags(t reflect.Type, prefix string, out *[]string, visiting map[reflect.Type]bool) {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil {
+		return
+	}
+	if t.Kind() == reflect.Map {
+		collectSecretTags(t.Elem(), joinPath(prefix, "*"), out, visiting)
+		return
+	}
+	if t.Kind() != reflect.Struct || visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		name, skip := jsonFieldName(f)
+		if skip {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			// 匿名嵌入结构体的字段会被 encoding/json 提升到外层
+			collectSecretTags(f.Type, prefix, out, visiting)
+			continue
+		}
+		if name == "" {
+			// 与 collectFlagKeys 一致：没有 json 标签时配置中的 key 为小写的字段名
+			name = strings.ToLower(f.Name)
+		}
+		path := joinPath(prefix, name)
+		if ok, _ := strconv.ParseBool(f.Tag.Get("secret")); ok {
+			*out = append(*out, path)
+			continue
+		}
+		collectSecretTags(f.Type, path, out, visiting)
+	}
+}
+
+// jsonFieldName 返回字段在 json 中的名称；skip 表示该字段被 `json:"-"` 忽略。
+func jsonFieldName(f reflect.StructField) (name string, skip bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ = strings.Cut(tag, ",")
+	return name, false
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestMatchPathPattern(t *testing.T) {
+	assert.True(t, matchPathPattern("*.password", "db.password"))
+	assert.False(t, matchPathPattern("*.password", "db.primary.password"))
+	assert.True(t, matchPathPattern("**.password", "db.primary.password"))
+	assert.True(t, matchPathPattern("**.token", "token"))
+	assert.True(t, matchPathPattern("db.*", "db.host"))
+	assert.False(t, matchPathPattern("db.*", "db"))
+	assert.True(t, matchPathPattern("api.key", "api.key"))
+}
+
+func TestDefaultConfig_Redacted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	content := "db:\n  host: 127.0.0.1\n  password: root\n  dsn: !secret mysql://root@tcp\napi:\n  token: abc\nlog:\n  level: info\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	cfg := NewDefaultConfig(
+		WithDecoder(decoder.YAMLDecoder{}),
+		WithSensitiveKeys("*.password"),
+	)
+	require.NoError(t, cfg.Load(NewFileSource(path)))
+
+	var target struct {
+		API struct {
+			Token string `json:"token" secret:"true"`
+		} `json:"api"`
+	}
+	require.NoError(t, cfg.Unmarshal(&target))
+	assert.Equal(t, "abc", target.API.Token)
+
+	red := cfg.Redacted()
+	assert.Equal(t, map[string]any{
+		"db": map[string]any{
+			"host":     "127.0.0.1",
+			"password": RedactedValue,
+			"dsn":      RedactedValue,
+		},
+		"api": map[string]any{"token": RedactedValue},
+		"log": map[string]any{"level": "info"},
+	}, red)
+
+	// 原始数据不受影响
+	v, _ := cfg.GetString("db.password")
+	assert.Equal(t, "root", v)
+	assert.True(t, cfg.IsSensitive("db.dsn"))
+	assert.False(t, cfg.IsSensitive("log.level"))
+}
+
+func TestDefaultConfig_RedactedUntaggedField(t *testing.T) {
+	cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+	require.NoError(t, cfg.Load(&switchSource{data: `{"db":{"host":"127.0.0.1","password":"root"}}`}))
+
+	// 没有 json 标签的字段按小写字段名匹配，与 encoding/json 的大小写不敏感匹配一致
+	var target struct {
+		DB struct {
+			Host     string
+			Password string `secret:"true"`
+		}
+	}
+	require.NoError(t, cfg.Unmarshal(&target))
+	assert.Equal(t, "root", target.DB.Password)
+	assert.True(t, cfg.IsSensitive("db.password"))
+	assert.Equal(t, map[string]any{"host": "127.0.0.1", "password": RedactedValue}, cfg.Redacted()["db"])
+}
+
+func TestDefaultConfig_RedactedExpandError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("db:\n  password: \"s3cret${env.X\"\n"), 0o644))
+
+	cfg := NewDefaultConfig(
+		WithDecoder(decoder.YAMLDecoder{}),
+		WithVariableExpander(DefaultVariableExpander{}),
+		WithSensitiveKeys("db.password"),
+	)
+	cfg.EnableEnvExpand()
+
+	err := cfg.Load(NewFileSource(path))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "db.password")
+	assert.NotContains(t, err.Error(), "s3cret")
+}
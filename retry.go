@@ -0,0 +1,291 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrCircuitOpen 表示熔断器处于打开状态，RetrySource 没有访问后端而是直接失败。
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// RetryPolicy 描述 RetrySource 的重试、退避与熔断行为。零值字段使用默认值。
+type RetryPolicy struct {
+	// MaxAttempts 是单次 Load 的最大尝试次数（含首次），默认 3；
+	// 为负数时不限制次数，仅受 MaxElapsed 与 ctx 约束。
+	MaxAttempts int
+	// MaxElapsed 是单次 Load 的最长总耗时（含退避等待），0 表示不限制。
+	MaxElapsed time.Duration
+	// InitialInterval 是首次重试前的等待时间，默认 100ms。
+	InitialInterval time.Duration
+	// MaxInterval 是退避等待的上限，默认 5s。
+	MaxInterval time.Duration
+	// Multiplier 是退避倍数，默认 2。
+	Multiplier float64
+	// Jitter 是随机抖动比例（0~1），实际等待时间在 interval*(1±Jitter) 之间，默认 0.2。
+	Jitter float64
+	// Retryable 判断错误是否值得重试，默认 IsRetryable。
+	Retryable func(error) bool
+
+	// BreakerThreshold 是触发熔断的连续失败 Load 次数（重试耗尽后计为一次失败），0 表示不启用熔断。
+	BreakerThreshold int
+	// BreakerCooldown 是熔断打开后的冷却时间，期间 Load 直接返回 ErrCircuitOpen，默认 30s。
+	// 冷却结束后放行一次探测请求，成功则关闭熔断，失败则重新打开。
+	BreakerCooldown time.Duration
+}
+
+// DefaultRetryPolicy 返回适用于远程 Source 的默认策略：最多 3 次尝试，100ms 起指数退避，
+// 连续 5 次 Load 失败后熔断 30s。
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:      3,
+		InitialInterval:  100 * time.Millisecond,
+		MaxInterval:      5 * time.Second,
+		Multiplier:       2,
+		Jitter:           0.2,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialInterval <= 0 {
+		p.InitialInterval = 100 * time.Millisecond
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = 5 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		p.Jitter = 0.2
+	}
+	if p.Retryable == nil {
+		p.Retryable = IsRetryable
+	}
+	if p.BreakerCooldown <= 0 {
+		p.BreakerCooldown = 30 * time.Second
+	}
+	return p
+}
+
+// backoff 返回第 attempt 次失败（从 1 开始）之后的等待时间。
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialInterval) * math.Pow(p.Multiplier, float64(attempt-1))
+	if d > float64(p.MaxInterval) {
+		d = float64(p.MaxInterval)
+	}
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
+
+// IsRetryable 是默认的重试判定，以下错误视为暂时性错误：
+//   - HTTP 5xx 与 429（*HTTPStatusError）
+//   - 超时：net.Error.Timeout()、context.DeadlineExceeded
+//   - 连接被拒绝/重置、响应提前结束
+//   - gRPC / etcd 的 Unavailable、DeadlineExceeded、ResourceExhausted、Aborted
+//
+// 内容不存在、SkipError、ctx 被取消、解析失败等错误不会重试。
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || isNotFound(err) {
+		return false
+	}
+	var skip *SkipError
+	if errors.As(err, &skip) {
+		return false
+	}
+
+	var httpErr *HTTPStatusError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return isRetryableCode(grpcCode(err))
+}
+
+// grpcCode 提取 gRPC 状态码，同时兼容 etcd 的 rpctypes.EtcdError。
+func grpcCode(err error) codes.Code {
+	var coder interface{ Code() codes.Code }
+	if errors.As(err, &coder) {
+		return coder.Code()
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code()
+	}
+	return codes.Unknown
+}
+
+func isRetryableCode(code codes.Code) bool {
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+// RetrySource 是 WithRetry 返回的装饰器，为任意 Source 增加重试、退避与熔断。
+type RetrySource struct {
+	src    Source
+	policy RetryPolicy
+
+	mu        sync.Mutex
+	failures  int       // 连续失败的 Load 次数
+	openUntil time.Time // 熔断打开的截止时间，零值表示关闭
+	probing   bool      // 半开状态下是否已有探测请求在进行
+	lastErr   error
+
+	// 便于测试替换
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+var _ ContextSource = (*RetrySource)(nil)
+
+// WithRetry 为 src 增加重试能力，适用于 HTTPSource、EtcdSource、ApolloSource 等远程 Source：
+//
+//	cfg.Load(WithRetry(NewEtcdSource(cli, "/app/config.yaml"), DefaultRetryPolicy()))
+//
+// 每次 Load 在遇到可重试错误时按指数退避（带抖动）重试，直到成功、达到 MaxAttempts / MaxElapsed
+// 或 ctx 结束。启用熔断后，连续多次 Load 失败会在冷却期内直接返回 ErrCircuitOpen，
+// 避免 watch 触发的频繁 reload 持续冲击已经不可用的后端。
+func WithRetry(src Source, policy RetryPolicy) *RetrySource {
+	return &RetrySource{
+		src:    src,
+		policy: policy.withDefaults(),
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+}
+
+// Name 返回被包装 Source 的名称。
+func (r *RetrySource) Name() string {
+	return sourceName(r.src, Metadata{})
+}
+
+// Unwrap 返回被包装的原始 Source。
+func (r *RetrySource) Unwrap() Source {
+	return r.src
+}
+
+// Load 实现 Source 接口。
+func (r *RetrySource) Load() ([]byte, Metadata, error) {
+	return r.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口。
+func (r *RetrySource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	if err := r.allow(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	start := r.now()
+	var (
+		data []byte
+		meta Metadata
+		err  error
+	)
+	for attempt := 1; ; attempt++ {
+		data, meta, err = loadSource(ctx, r.src)
+		if err == nil || !r.policy.Retryable(err) || ctx.Err() != nil {
+			break
+		}
+		if r.policy.MaxAttempts > 0 && attempt >= r.policy.MaxAttempts {
+			err = fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+			break
+		}
+		wait := r.policy.backoff(attempt)
+		if r.policy.MaxElapsed > 0 && r.now().Sub(start)+wait > r.policy.MaxElapsed {
+			err = fmt.Errorf("giving up after %d attempts (max elapsed %s): %w", attempt, r.policy.MaxElapsed, err)
+			break
+		}
+		if serr := r.sleep(ctx, wait); serr != nil {
+			break
+		}
+	}
+
+	r.record(ctx, err)
+	return data, meta, err
+}
+
+// allow 检查熔断状态，冷却结束后只放行一个探测请求。
+func (r *RetrySource) allow() error {
+	if r.policy.BreakerThreshold <= 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.openUntil.IsZero() {
+		return nil
+	}
+	if r.now().Before(r.openUntil) || r.probing {
+		return fmt.Errorf("%w for %s (last error: %v)", ErrCircuitOpen, r.Name(), r.lastErr)
+	}
+	r.probing = true
+	return nil
+}
+
+// record 根据一次 Load 的最终结果更新熔断状态。
+// 内容不存在等不可重试的错误说明后端是可达的，不计入连续失败；调用方 ctx 被取消或超时则不影响熔断状态。
+func (r *RetrySource) record(ctx context.Context, err error) {
+	if r.policy.BreakerThreshold <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.probing = false
+	// 调用方取消或自身 ctx 超时不代表 Source 不健康，不计入熔断
+	if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+		return
+	}
+	if err == nil || !r.policy.Retryable(err) {
+		r.failures, r.openUntil, r.lastErr = 0, time.Time{}, nil
+		return
+	}
+	r.failures++
+	r.lastErr = err
+	if r.failures >= r.policy.BreakerThreshold {
+		r.openUntil = r.now().Add(r.policy.BreakerCooldown)
+	}
+}
+
+// sleepContext 等待 d，ctx 结束时提前返回 ctx.Err()。
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flakySource 前 failures 次 Load 返回 err，之后成功。
+type flakySource struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakySource) Load() ([]byte, Metadata, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, Metadata{}, f.err
+	}
+	return []byte(`{"a":1}`), Metadata{Format: "json", Source: "flaky"}, nil
+}
+
+func newTestRetry(src Source, policy RetryPolicy) (*RetrySource, *time.Time) {
+	now := time.Unix(0, 0)
+	r := WithRetry(src, policy)
+	r.now = func() time.Time { return now }
+	r.sleep = func(_ context.Context, d time.Duration) error {
+		now = now.Add(d)
+		return nil
+	}
+	return r, &now
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&HTTPStatusError{StatusCode: 503}, true},
+		{fmt.Errorf("wrap: %w", &HTTPStatusError{StatusCode: 429}), true},
+		{&HTTPStatusError{StatusCode: 404}, false},
+		{&HTTPStatusError{StatusCode: 400}, false},
+		{context.DeadlineExceeded, true},
+		{context.Canceled, false},
+		{fmt.Errorf("EtcdSource: get key failed: %w", status.Error(codes.Unavailable, "no leader")), true},
+		{status.Error(codes.PermissionDenied, "denied"), false},
+		{&SkipError{Source: "x", Reason: ErrSourceNotFound}, false},
+		{errors.New("boom"), false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, IsRetryable(c.err), "%v", c.err)
+	}
+}
+
+func TestRetrySource(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		src := &flakySource{failures: 2, err: &HTTPStatusError{StatusCode: 502}}
+		r, _ := newTestRetry(src, RetryPolicy{MaxAttempts: 3})
+		data, _, err := r.Load()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"a":1}`, string(data))
+		assert.Equal(t, 3, src.calls)
+	})
+
+	t.Run("MaxAttempts", func(t *testing.T) {
+		src := &flakySource{failures: 10, err: &HTTPStatusError{StatusCode: 500}}
+		r, _ := newTestRetry(src, RetryPolicy{MaxAttempts: 2})
+		_, _, err := r.Load()
+		assert.ErrorContains(t, err, "giving up after 2 attempts")
+		assert.Equal(t, 2, src.calls)
+	})
+
+	t.Run("MaxElapsed", func(t *testing.T) {
+		src := &flakySource{failures: 100, err: context.DeadlineExceeded}
+		r, now := newTestRetry(src, RetryPolicy{
+			MaxAttempts: -1, MaxElapsed: time.Second,
+			InitialInterval: 100 * time.Millisecond, Multiplier: 2, Jitter: 0,
+		})
+		_, _, err := r.Load()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		// 100+200+400 = 700ms，下一次 800ms 会超过 1s
+		assert.Equal(t, 4, src.calls)
+		assert.Equal(t, 700*time.Millisecond, now.Sub(time.Unix(0, 0)))
+	})
+
+	t.Run("NotRetryable", func(t *testing.T) {
+		src := &flakySource{failures: 1, err: ErrSourceNotFound}
+		r, _ := newTestRetry(src, RetryPolicy{})
+		_, _, err := r.Load()
+		assert.ErrorIs(t, err, ErrSourceNotFound)
+		assert.Equal(t, 1, src.calls)
+	})
+
+	t.Run("CircuitBreaker", func(t *testing.T) {
+		src := &flakySource{failures: 4, err: &HTTPStatusError{StatusCode: 503}}
+		r, now := newTestRetry(src, RetryPolicy{MaxAttempts: 1, BreakerThreshold: 2, BreakerCooldown: time.Minute})
+
+		for i := 0; i < 2; i++ {
+			_, _, err := r.Load()
+			assert.NotErrorIs(t, err, ErrCircuitOpen)
+		}
+		_, _, err := r.Load()
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.Equal(t, 2, src.calls)
+
+		// 冷却后放行一次探测，仍失败则重新打开
+		*now = now.Add(time.Minute)
+		_, _, err = r.Load()
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+		_, _, err = r.Load()
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+
+		*now = now.Add(time.Minute)
+		_, _, _ = r.Load() // 第 4 次失败
+		*now = now.Add(time.Minute)
+		_, _, err = r.Load()
+		require.NoError(t, err)
+		assert.Equal(t, 5, src.calls)
+	})
+
+	t.Run("CallerDeadlineNotCounted", func(t *testing.T) {
+		// 调用方的 ctx 已过期，后端本身是健康的
+		src := &flakySource{}
+		r, _ := newTestRetry(src, RetryPolicy{MaxAttempts: 1, BreakerThreshold: 1, BreakerCooldown: time.Minute})
+
+		ctx, cancel := context.WithDeadline(context.Background(), time.Unix(0, 0))
+		defer cancel()
+		for i := 0; i < 2; i++ {
+			_, _, err := r.LoadContext(ctx)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.NotErrorIs(t, err, ErrCircuitOpen)
+		}
+		_, _, err := r.Load()
+		require.NoError(t, err)
+		assert.Equal(t, 1, src.calls)
+	})
+
+	t.Run("Unwrap", func(t *testing.T) {
+		file := NewFileSource("testdata/base.yaml")
+		r := WithRetry(Optional(file), DefaultRetryPolicy())
+		got, ok := findSource[*FileSource](r)
+		require.True(t, ok)
+		assert.Same(t, file, got)
+		assert.Equal(t, "testdata/base.yaml", r.Name())
+	})
+}
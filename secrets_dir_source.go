@@ -0,0 +1,232 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultSecretsDir 是 Docker / Swarm 挂载 secret 的默认目录
+	DefaultSecretsDir = "/run/secrets"
+	// defaultSecretMaxSize 是单个 secret 文件的默认大小上限
+	defaultSecretMaxSize = 1 << 20
+)
+
+// SecretsDirSource 把目录中的每个文件转换为一个配置 key，适用于 Docker secrets（/run/secrets）
+// 与 systemd credentials（$CREDENTIALS_DIRECTORY）这类一个文件一个密钥的布局。
+//
+// 默认行为：
+//   - 文件名中的 "__" 表示层级，例如 db__password => db.password（见 WithSecretsDirSeparator）
+//   - 去掉值末尾的换行符（echo "xxx" > file 产生的 \n 或 \r\n）
+//   - 单个文件超过 1 MiB 时返回错误（见 WithSecretsDirMaxSize）
+//   - 所有值都标记为敏感，在 Redacted()、导出与错误信息中被脱敏
+//
+// 以 "." 开头的文件与子目录会被忽略。
+type SecretsDirSource struct {
+	dir          string
+	name         string
+	prefix       string
+	separator    string
+	mapper       func(filename string) string
+	maxSize      int64
+	keepNewline  bool
+	notSensitive bool
+}
+
+var _ ContextSource = (*SecretsDirSource)(nil)
+
+// SecretsDirOption 用于在 NewSecretsDirSource 中配置 SecretsDirSource 的可选参数。
+type SecretsDirOption func(*SecretsDirSource)
+
+// WithSecretsDirName 指定该 Source 的名称，默认为目录路径。
+func WithSecretsDirName(name string) SecretsDirOption {
+	return func(s *SecretsDirSource) {
+		if strings.TrimSpace(name) != "" {
+			s.name = name
+		}
+	}
+}
+
+// WithSecretsDirPrefix 为所有 key 添加路径前缀，例如 "secrets" 时文件 db__password 对应 secrets.db.password。
+func WithSecretsDirPrefix(prefix string) SecretsDirOption {
+	return func(s *SecretsDirSource) {
+		s.prefix = strings.Trim(strings.TrimSpace(prefix), ".")
+	}
+}
+
+// WithSecretsDirSeparator 指定文件名中表示层级的分隔符，默认 "__"。
+func WithSecretsDirSeparator(sep string) SecretsDirOption {
+	return func(s *SecretsDirSource) {
+		if sep != "" {
+			s.separator = sep
+		}
+	}
+}
+
+// WithSecretsDirKeyMapper 自定义文件名到配置路径（以 "." 分隔）的映射，返回空字符串表示忽略该文件。
+// 设置后 WithSecretsDirSeparator 不再生效：
+//
+//	WithSecretsDirKeyMapper(func(name string) string {
+//	    return strings.ToLower(strings.ReplaceAll(name, "_", "."))
+//	})
+func WithSecretsDirKeyMapper(mapper func(filename string) string) SecretsDirOption {
+	return func(s *SecretsDirSource) {
+		s.mapper = mapper
+	}
+}
+
+// WithSecretsDirMaxSize 设置单个文件的大小上限（字节），n <= 0 表示不限制。
+func WithSecretsDirMaxSize(n int64) SecretsDirOption {
+	return func(s *SecretsDirSource) {
+		s.maxSize = n
+	}
+}
+
+// WithSecretsDirKeepNewline 保留值末尾的换行符。
+func WithSecretsDirKeepNewline() SecretsDirOption {
+	return func(s *SecretsDirSource) {
+		s.keepNewline = true
+	}
+}
+
+// WithSecretsDirSensitive 设置值是否标记为敏感，默认 true。
+func WithSecretsDirSensitive(sensitive bool) SecretsDirOption {
+	return func(s *SecretsDirSource) {
+		s.notSensitive = !sensitive
+	}
+}
+
+// NewSecretsDirSource 创建 secret 目录配置源。dir 为空时依次使用 $CREDENTIALS_DIRECTORY 与 /run/secrets：
+//
+//	// systemd: LoadCredential=db__password:/etc/myapp/db.pass
+//	cfg.Load(
+//	    NewFileSource("config/app.yaml"),
+//	    Optional(NewSecretsDirSource("")), // 目录不存在时跳过
+//	)
+//	pw, _ := cfg.GetString("db.password")
+func NewSecretsDirSource(dir string, opts ...SecretsDirOption) *SecretsDirSource {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		dir = os.Getenv("CREDENTIALS_DIRECTORY")
+	}
+	if dir == "" {
+		dir = DefaultSecretsDir
+	}
+
+	s := &SecretsDirSource{dir: dir, separator: "__", maxSize: defaultSecretMaxSize}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.name == "" {
+		s.name = s.dir
+	}
+	return s
+}
+
+// Name 返回该 Source 的名称。
+func (s *SecretsDirSource) Name() string {
+	return s.name
+}
+
+// Sensitive 返回该 Source 的值是否标记为敏感，见 WithSecretsDirSensitive。
+func (s *SecretsDirSource) Sensitive() bool {
+	return !s.notSensitive
+}
+
+// Load 实现 Source 接口。
+func (s *SecretsDirSource) Load() ([]byte, Metadata, error) {
+	return s.LoadContext(context.Background())
+}
+
+// LoadContext 实现 ContextSource 接口，把所有 secret 文件转换为 JSON 交给 JSON Decoder 解析。
+func (s *SecretsDirSource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, Metadata{}, fmt.Errorf("SecretsDirSource: read dir %q failed: %w", s.dir, err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	out := make(map[string]any, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(s.dir, name)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		key := s.keyPath(name)
+		if key == "" {
+			continue
+		}
+
+		value, err := s.readSecret(path, info.Size())
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+		insertNestedValue(out, strings.Split(key, "."), value)
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	return data, Metadata{Format: "json", Source: s.name}, nil
+}
+
+// keyPath 返回文件名对应的配置路径。
+func (s *SecretsDirSource) keyPath(name string) string {
+	var key string
+	if s.mapper != nil {
+		key = s.mapper(name)
+	} else {
+		key = strings.ReplaceAll(name, s.separator, ".")
+	}
+	key = strings.Trim(key, ".")
+	if key == "" {
+		return ""
+	}
+	return joinPath(s.prefix, key)
+}
+
+// readSecret 读取单个 secret 文件，超过大小上限时返回错误。
+func (s *SecretsDirSource) readSecret(path string, size int64) (string, error) {
+	if s.maxSize > 0 && size > s.maxSize {
+		return "", fmt.Errorf("SecretsDirSource: secret %q is %d bytes, exceeds limit of %d bytes", path, size, s.maxSize)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("SecretsDirSource: read secret %q failed: %w", path, err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if s.maxSize > 0 {
+		// 文件可能在 Stat 之后被替换，读取时再限制一次
+		r = io.LimitReader(f, s.maxSize+1)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("SecretsDirSource: read secret %q failed: %w", path, err)
+	}
+	if s.maxSize > 0 && int64(len(b)) > s.maxSize {
+		return "", fmt.Errorf("SecretsDirSource: secret %q exceeds limit of %d bytes", path, s.maxSize)
+	}
+
+	value := string(b)
+	if !s.keepNewline {
+		value = strings.TrimRight(value, "\r\n")
+	}
+	return value, nil
+}
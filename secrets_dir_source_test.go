@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func writeSecrets(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	return dir
+}
+
+func TestSecretsDirSource(t *testing.T) {
+	dir := writeSecrets(t, map[string]string{
+		"db__password": "s3cret\n",
+		"api_token":    "tok\r\n",
+		".hidden":      "x",
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+		require.NoError(t, cfg.Load(NewSecretsDirSource(dir)))
+
+		pw, _ := cfg.GetString("db.password")
+		tok, _ := cfg.GetString("api_token")
+		assert.Equal(t, "s3cret", pw)
+		assert.Equal(t, "tok", tok)
+		assert.ElementsMatch(t, []string{"db.password", "api_token"}, cfg.Keys())
+
+		assert.True(t, cfg.IsSensitive("db.password"))
+		assert.Equal(t, RedactedValue, cfg.Redacted()["api_token"])
+	})
+
+	t.Run("options", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+		require.NoError(t, cfg.Load(NewSecretsDirSource(dir,
+			WithSecretsDirPrefix("secrets"),
+			WithSecretsDirKeyMapper(func(name string) string { return strings.ReplaceAll(name, "_", ".") }),
+			WithSecretsDirKeepNewline(),
+			WithSecretsDirSensitive(false),
+		)))
+
+		tok, _ := cfg.GetString("secrets.api.token")
+		assert.Equal(t, "tok\r\n", tok)
+		assert.False(t, cfg.IsSensitive("secrets.api.token"))
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+		err := cfg.Load(NewSecretsDirSource(dir, WithSecretsDirMaxSize(4)))
+		assert.ErrorContains(t, err, "exceeds limit of 4 bytes")
+	})
+
+	t.Run("credentials directory", func(t *testing.T) {
+		t.Setenv("CREDENTIALS_DIRECTORY", dir)
+		assert.Equal(t, dir, NewSecretsDirSource("").Name())
+	})
+
+	t.Run("missing dir is optional", func(t *testing.T) {
+		cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+		require.NoError(t, cfg.Load(Optional(NewSecretsDirSource(filepath.Join(dir, "missing")))))
+		assert.True(t, cfg.Layers()[0].Skipped)
+	})
+}
+
+func TestSensitive(t *testing.T) {
+	cfg := NewDefaultConfig(WithDecoder(decoder.JSONDecoder{}))
+	require.NoError(t, cfg.Load(&switchSource{data: `{"a": 1}`}, Sensitive(&switchSource{data: `{"b": {"c": 2}}`})))
+	assert.False(t, cfg.IsSensitive("a"))
+	assert.True(t, cfg.IsSensitive("b.c"))
+}
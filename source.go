@@ -1,8 +1,100 @@
 package config
 
+import "context"
+
 // Source 用于提供原始配置内容。
 // 可以是 JSON 文件、YAML 文件、env map、ETCD、Consul、Git、HTTP 配置等。
 type Source interface {
 	// Load 返回该 Source 的配置字节和元数据（格式等）
 	Load() ([]byte, Metadata, error)
 }
+
+// ContextSource 是支持 context 的 Source。
+// DefaultConfig.LoadContext 会把 ctx 的取消信号和截止时间传递给 LoadContext，
+// 内置的所有 Source 均实现了该接口。
+type ContextSource interface {
+	Source
+
+	// LoadContext 与 Load 相同，但在 ctx 取消或超时后应尽快返回 ctx.Err()。
+	LoadContext(ctx context.Context) ([]byte, Metadata, error)
+}
+
+// AsContextSource 将任意 Source 适配为 ContextSource。
+// 如果 src 已实现 ContextSource 则原样返回；否则包装为 legacy 适配器：
+// 在独立 goroutine 中执行 Load，ctx 结束时立即返回 ctx.Err()（底层 Load 仍会在后台执行完毕）。
+func AsContextSource(src Source) ContextSource {
+	if cs, ok := src.(ContextSource); ok {
+		return cs
+	}
+	return legacySource{src: src}
+}
+
+// legacySource 是只实现了 Load 的旧 Source 的适配器。
+type legacySource struct {
+	src Source
+}
+
+func (l legacySource) Load() ([]byte, Metadata, error) {
+	return l.src.Load()
+}
+
+func (l legacySource) LoadContext(ctx context.Context) ([]byte, Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	type result struct {
+		data []byte
+		meta Metadata
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		data, meta, err := l.src.Load()
+		ch <- result{data: data, meta: meta, err: err}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.data, r.meta, r.err
+	case <-ctx.Done():
+		return nil, Metadata{}, ctx.Err()
+	}
+}
+
+// Unwrap 返回被适配的原始 Source。
+func (l legacySource) Unwrap() Source {
+	return l.src
+}
+
+// loadSource 以 ctx 加载 src，是 DefaultConfig 调用 Source 的统一入口。
+func loadSource(ctx context.Context, src Source) ([]byte, Metadata, error) {
+	return AsContextSource(src).LoadContext(ctx)
+}
+
+// findSource 沿着装饰器的 Unwrap() Source 链查找第一个类型为 T 的 Source。
+// 用于在 Optional、legacy 适配器等包装之后识别原始 Source（例如 *FileSource）。
+//
+// LayeredSource 展开出的层在自身的链上找不到时，继续在所属 Source 的链上查找，
+// 因此包装在 LayeredSource 外面的装饰器（Required、Locked、WithTrust 等）对每个展开的层都生效。
+func findSource[T any](src Source) (T, bool) {
+	var parent Source
+	for src != nil {
+		if t, ok := src.(T); ok {
+			return t, true
+		}
+		if p, ok := src.(interface{ Parent() Source }); ok && parent == nil {
+			parent = p.Parent()
+		}
+		var next Source
+		if u, ok := src.(interface{ Unwrap() Source }); ok {
+			next = u.Unwrap()
+		}
+		if next == nil {
+			next, parent = parent, nil
+		}
+		src = next
+	}
+	var zero T
+	return zero, false
+}
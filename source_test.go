@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+// blockingSource 是只实现了 Load 的 legacy Source，Load 会阻塞到 release 关闭。
+type blockingSource struct {
+	release chan struct{}
+}
+
+func (b blockingSource) Load() ([]byte, Metadata, error) {
+	<-b.release
+	return []byte(`{"a":1}`), Metadata{Format: "json", Source: "blocking"}, nil
+}
+
+func TestAsContextSource(t *testing.T) {
+	t.Run("Legacy_Cancel", func(t *testing.T) {
+		src := blockingSource{release: make(chan struct{})}
+		defer close(src.release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, _, err := AsContextSource(src).LoadContext(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Native", func(t *testing.T) {
+		src := NewFileSource("testdata/base.yaml")
+		assert.Same(t, src, AsContextSource(src))
+	})
+}
+
+func TestDefaultConfig_LoadContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}), WithDecoder(decoder.JSONDecoder{}))
+	require.NoError(t, cfg.Load(NewFileSource("testdata/base.yaml")))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := cfg.LoadContext(ctx,
+		NewFileSource("testdata/base.yaml"),
+		NewHTTPSource(srv.URL+"/app.json"),
+	)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	var se *SourceError
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, srv.URL+"/app.json", se.Source)
+
+	// 失败的加载不会影响已有配置
+	port, ok := cfg.GetInt("server.port")
+	assert.True(t, ok)
+	assert.Equal(t, 8080, port)
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SourceStatus 描述一个 Source 跨多次 Load 累计的健康状态。
+type SourceStatus struct {
+	// Name 是 Source 名称，与 LayerInfo.Name 一致
+	Name string
+	// Required 表示该 Source 失败时会中断 Load，见 Required / BestEffort
+	Required bool
+	// LastAttempt 是最近一次加载该 Source 的时间
+	LastAttempt time.Time
+	// LastSuccess 是最近一次成功加载的时间，从未成功时为零值
+	LastSuccess time.Time
+	// LastError 是最近一次失败的错误，成功后清空
+	LastError error
+	// FailingSince 是连续失败开始的时间，当前健康时为零值
+	FailingSince time.Time
+	// ConsecutiveFailures 是连续失败的次数
+	ConsecutiveFailures int
+	// Bytes 是最近一次成功加载的字节数
+	Bytes int
+	// Latency 是最近一次加载的耗时
+	Latency time.Duration
+	// Revision 是最近一次成功加载的版本标识（etcd ModRevision、Apollo releaseKey、HTTP ETag 等）
+	Revision string
+	// Stale 表示当前数据来自缓存而不是后端，见 WithDiskCache
+	Stale bool
+	// CircuitOpen 表示熔断器处于打开状态，见 WithRetry
+	CircuitOpen bool
+}
+
+// FailingFor 返回截至 now 已连续失败的时长，当前健康时返回 0。
+func (s SourceStatus) FailingFor(now time.Time) time.Duration {
+	if s.FailingSince.IsZero() {
+		return 0
+	}
+	return now.Sub(s.FailingSince)
+}
+
+// StatusReporter 是 Source 的可选接口，用于补充 DefaultConfig 无法从 Load 结果中得知的状态，
+// 例如缓存是否陈旧、熔断器是否打开。
+//
+// DefaultConfig.Status 会沿着装饰器链（Unwrap）依次询问每一层，返回值中的非零字段会合并到结果中。
+type StatusReporter interface {
+	ReportStatus() SourceStatus
+}
+
+// ReportStatus 实现 StatusReporter 接口。
+// 使用缓存时 LastError 为导致回退的后端错误，FailingSince 为后端开始失败的时间，
+// 因此 CheckReady 能发现长期依赖陈旧缓存的 Source。
+func (d *DiskCacheSource) ReportStatus() SourceStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return SourceStatus{Stale: d.stale, LastError: d.lastErr, FailingSince: d.failingSince}
+}
+
+// ReportStatus 实现 StatusReporter 接口。
+func (r *RetrySource) ReportStatus() SourceStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return SourceStatus{CircuitOpen: !r.openUntil.IsZero() && r.now().Before(r.openUntil)}
+}
+
+// sourceStatusEntry 是 DefaultConfig 内部保存的状态及对应的 Source。
+type sourceStatusEntry struct {
+	status SourceStatus
+	src    Source
+}
+
+// recordStatus 根据一次 Load 的层信息更新每个 Source 的状态，调用方需持有写锁。
+// 被跳过的层（例如可选文件不存在）视为成功。
+func (c *DefaultConfig) recordStatus(stack []stackEntry, layers []LayerInfo, at time.Time) {
+	for i, l := range layers {
+		// layers 与 stack 按相同顺序生成，Load 中断时 layers 只是 stack 的前缀
+		src := stack[i].src
+
+		var entry *sourceStatusEntry
+		for _, e := range c.statuses {
+			if e.status.Name == l.Name {
+				entry = e
+				break
+			}
+		}
+		if entry == nil {
+			entry = &sourceStatusEntry{status: SourceStatus{Name: l.Name}}
+			c.statuses = append(c.statuses, entry)
+		}
+
+		st := &entry.status
+		entry.src = src
+		st.Required = l.Required
+		st.LastAttempt = at
+		st.Latency = l.Duration
+		if l.Failed {
+			if st.FailingSince.IsZero() {
+				st.FailingSince = at
+			}
+			st.LastError = l.Err
+			st.ConsecutiveFailures++
+			continue
+		}
+		st.LastSuccess = at
+		st.LastError, st.FailingSince, st.ConsecutiveFailures = nil, time.Time{}, 0
+		st.Bytes = l.Bytes
+		st.Revision = l.Revision
+	}
+}
+
+// Status 返回每个 Source 的健康状态，按首次加载的顺序排列。
+// 状态跨多次 Load 累计：失败的 Load 同样会更新状态，便于就绪探针判断远程 Source 是否持续异常。
+func (c *DefaultConfig) Status() []SourceStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make([]SourceStatus, 0, len(c.statuses))
+	for _, e := range c.statuses {
+		st := e.status
+		for src := e.src; src != nil; {
+			if r, ok := src.(StatusReporter); ok {
+				mergeReportedStatus(&st, r.ReportStatus())
+			}
+			u, ok := src.(interface{ Unwrap() Source })
+			if !ok {
+				break
+			}
+			src = u.Unwrap()
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// mergeReportedStatus 把 StatusReporter 报告的非零字段合并到 dst。
+func mergeReportedStatus(dst *SourceStatus, r SourceStatus) {
+	dst.Stale = dst.Stale || r.Stale
+	dst.CircuitOpen = dst.CircuitOpen || r.CircuitOpen
+	if dst.Revision == "" {
+		dst.Revision = r.Revision
+	}
+	if dst.LastError == nil {
+		dst.LastError = r.LastError
+	}
+	if dst.FailingSince.IsZero() {
+		dst.FailingSince = r.FailingSince
+	}
+}
+
+// CheckReady 用于就绪探针：当任一 required Source 已连续失败超过 threshold 时返回错误。
+// threshold 为 0 时任何 required Source 的失败都视为未就绪。尚未执行过 Load 时同样返回错误。
+func (c *DefaultConfig) CheckReady(threshold time.Duration) error {
+	statuses := c.Status()
+	if len(statuses) == 0 {
+		return fmt.Errorf("config not loaded yet")
+	}
+
+	now := time.Now()
+	var failing []string
+	for _, st := range statuses {
+		if !st.Required || st.FailingSince.IsZero() {
+			continue
+		}
+		if d := st.FailingFor(now); d >= threshold {
+			failing = append(failing, fmt.Sprintf("%s: failing for %s: %v", st.Name, d.Truncate(time.Millisecond), st.LastError))
+		}
+	}
+	if len(failing) > 0 {
+		return fmt.Errorf("config not ready: %s", strings.Join(failing, "; "))
+	}
+	return nil
+}
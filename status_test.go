@@ -0,0 +1,81 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestDefaultConfig_Status(t *testing.T) {
+	healthy := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("ETag", `"v42"`)
+		_, _ = w.Write([]byte(`{"feature":true}`))
+	}))
+	defer srv.Close()
+
+	remote := WithDiskCache(NewHTTPSource(srv.URL+"/app.json", WithHTTPSourceName("remote")), t.TempDir())
+	cfg := NewDefaultConfig(WithDecoder(decoder.YAMLDecoder{}), WithDecoder(decoder.JSONDecoder{}))
+	assert.Error(t, cfg.CheckReady(time.Minute))
+
+	require.NoError(t, cfg.Load(NewFileSource("testdata/base.yaml"), remote))
+	statuses := cfg.Status()
+	require.Len(t, statuses, 2)
+	st := statuses[1]
+	assert.Equal(t, "remote", st.Name)
+	assert.True(t, st.Required)
+	assert.Equal(t, `"v42"`, st.Revision)
+	assert.Equal(t, len(`{"feature":true}`), st.Bytes)
+	assert.False(t, st.LastSuccess.IsZero())
+	assert.Positive(t, st.Latency)
+	assert.NoError(t, cfg.CheckReady(0))
+
+	// 后端故障：磁盘缓存兜底，Load 成功但数据陈旧
+	healthy = false
+	require.NoError(t, cfg.Load(NewFileSource("testdata/base.yaml"), remote))
+	st = cfg.Status()[1]
+	assert.True(t, st.Stale)
+	assert.ErrorContains(t, st.LastError, "503")
+	assert.False(t, st.FailingSince.IsZero())
+	// 陈旧缓存同样计入失败时长，超过阈值后不再就绪
+	assert.Error(t, cfg.CheckReady(0))
+	assert.NoError(t, cfg.CheckReady(time.Hour))
+	since := st.FailingSince
+	require.NoError(t, cfg.Load(NewFileSource("testdata/base.yaml"), remote))
+	assert.Equal(t, since, cfg.Status()[1].FailingSince)
+
+	// 后端恢复后清空
+	healthy = true
+	require.NoError(t, cfg.Load(NewFileSource("testdata/base.yaml"), remote))
+	st = cfg.Status()[1]
+	assert.False(t, st.Stale)
+	assert.True(t, st.FailingSince.IsZero())
+	assert.NoError(t, cfg.CheckReady(0))
+	healthy = false
+
+	// 没有兜底的 required Source 持续失败时就绪检查失败
+	bare := NewHTTPSource(srv.URL+"/app.json", WithHTTPSourceName("remote"))
+	require.Error(t, cfg.Load(NewFileSource("testdata/base.yaml"), bare))
+	require.Error(t, cfg.Load(NewFileSource("testdata/base.yaml"), bare))
+	st = cfg.Status()[1]
+	assert.Equal(t, 2, st.ConsecutiveFailures)
+	assert.False(t, st.FailingSince.IsZero())
+	assert.Equal(t, `"v42"`, st.Revision) // 保留最近一次成功的版本
+	assert.Error(t, cfg.CheckReady(0))
+	assert.NoError(t, cfg.CheckReady(time.Hour))
+
+	healthy = true
+	require.NoError(t, cfg.Load(NewFileSource("testdata/base.yaml"), bare))
+	assert.NoError(t, cfg.CheckReady(0))
+	assert.Zero(t, cfg.Status()[1].ConsecutiveFailures)
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// overrideSourceName 是运行期覆盖层在 Position 等信息中的名称。
+const overrideSourceName = "override"
+
+var (
+	// ErrConfigFileChanged 表示目标文件在加载之后被外部修改过，SafeWriteConfig 拒绝覆盖。
+	ErrConfigFileChanged = errors.New("config file changed since it was loaded")
+
+	// ErrConfigFileExists 表示目标文件已存在且从未被加载过，SafeWriteConfig 拒绝覆盖。
+	ErrConfigFileExists = errors.New("config file already exists")
+
+	// ErrSensitiveWrite 表示要写出的配置包含来自敏感 Source（见 Sensitive、SecretsDirSource）的值。
+	ErrSensitiveWrite = errors.New("config contains values from sensitive sources")
+)
+
+// Set 在运行期覆盖某个路径的值，例如管理后台下发的临时配置。
+// 覆盖层优先级最高：后续每次 Load 都会在所有 Source 之后重新应用。
+func (c *DefaultConfig) Set(path string, value any) {
+	parts := strings.Split(path, ".")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.overrides == nil {
+		c.overrides = make(map[string]any)
+	}
+	insertNestedValue(c.overrides, parts, cloneValue(value))
+
+	if c.data == nil {
+		c.data = make(map[string]any)
+	}
+	insertNestedValue(c.data, parts, cloneValue(value))
+	if c.raw == nil {
+		c.raw = make(map[string]any)
+	}
+	insertNestedValue(c.raw, parts, cloneValue(value))
+	recordPositions(c.positions, "", map[string]any{path: value}, overrideSourceName, nil)
+}
+
+// WriteOption 用于配置 WriteConfig / SafeWriteConfig 的行为。
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	overridesOnly bool
+	perm          os.FileMode
+}
+
+// WithWriteOverridesOnly 只写出通过 Set 设置的覆盖层，而不是完整的生效配置。
+// 适合把运行期覆盖持久化为一个独立的 overrides 文件，下次启动时作为最后一个 Source 加载。
+func WithWriteOverridesOnly() WriteOption {
+	return func(o *writeOptions) {
+		o.overridesOnly = true
+	}
+}
+
+// WithWritePerm 指定新建文件的权限，默认 0600；已存在的文件保留原有权限。
+func WithWritePerm(perm os.FileMode) WriteOption {
+	return func(o *writeOptions) {
+		o.perm = perm
+	}
+}
+
+// WriteConfig 把当前配置写入 path，格式由文件扩展名推断（见 detectFormatFromPath），
+// 需要通过 WithEncoder 注册对应格式的 Encoder。
+//
+// 写入是原子的：先写临时文件并 fsync，再 rename 覆盖目标文件。
+// 与 MarshalTo 不同，写出的是真实值，不做脱敏；但写出的是变量替换与解密之前的内容，
+// ${VAR} 与 ENC(...) 保持原样。配置中包含来自敏感 Source（Sensitive、SecretsDirSource）的值时
+// 返回 ErrSensitiveWrite，避免把独立存放的密钥以明文写入配置文件；此时可以改用 WithWriteOverridesOnly。
+func (c *DefaultConfig) WriteConfig(path string, opts ...WriteOption) error {
+	return c.writeConfig(path, false, opts...)
+}
+
+// SafeWriteConfig 与 WriteConfig 相同，但会拒绝覆盖以下文件：
+//   - 通过 FileSource 加载过、但磁盘内容在加载之后被修改过（ErrConfigFileChanged）
+//   - 已存在、但从未被加载过（ErrConfigFileExists）
+//
+// 检查与替换之间不持有文件锁，只用于防止覆盖他人的手工修改。
+func (c *DefaultConfig) SafeWriteConfig(path string, opts ...WriteOption) error {
+	return c.writeConfig(path, true, opts...)
+}
+
+func (c *DefaultConfig) writeConfig(path string, safe bool, opts ...WriteOption) error {
+	o := writeOptions{perm: 0o600}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	format, err := detectFormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	var data map[string]any
+	var leaked []string
+	if o.overridesOnly {
+		data = cloneMap(c.overrides)
+	} else {
+		data = cloneMap(c.raw)
+		for p := range c.sourceSecrets {
+			if _, ok := getByPath(data, p); ok {
+				leaked = append(leaked, p)
+			}
+		}
+	}
+	c.mu.RUnlock()
+	if len(leaked) > 0 {
+		sort.Strings(leaked)
+		return fmt.Errorf("write config %q refused: %w: %s", path, ErrSensitiveWrite, strings.Join(leaked, ", "))
+	}
+	if data == nil {
+		data = make(map[string]any)
+	}
+
+	b, err := c.encode(format, data)
+	if err != nil {
+		return err
+	}
+
+	key := fileDigestKey(path)
+	perm := o.perm
+	current, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if safe {
+			c.mu.RLock()
+			digest, loaded := c.fileDigests[key]
+			c.mu.RUnlock()
+			if !loaded {
+				return fmt.Errorf("write config %q refused: %w", path, ErrConfigFileExists)
+			}
+			if sum := sha256.Sum256(current); !bytes.Equal(sum[:], digest[:]) {
+				return fmt.Errorf("write config %q refused: %w", path, ErrConfigFileChanged)
+			}
+		}
+		if fi, err := os.Stat(path); err == nil {
+			perm = fi.Mode().Perm()
+		}
+	case errors.Is(err, fs.ErrNotExist):
+		// 新文件，直接写入
+	default:
+		return fmt.Errorf("read config %q failed: %w", path, err)
+	}
+
+	if err := writeFileAtomic(path, b, perm); err != nil {
+		return fmt.Errorf("write config %q failed: %w", path, err)
+	}
+
+	// 写入成功后更新摘要，后续的 SafeWriteConfig 以本次写入的内容为基准
+	c.mu.Lock()
+	c.fileDigests[key] = sha256.Sum256(b)
+	c.mu.Unlock()
+	return nil
+}
+
+// recordFileDigest 记录从本地文件系统加载的文件内容摘要，供 SafeWriteConfig 检测外部修改。
+// 通过 fs.FS 加载的文件无法写回，不做记录。
+func recordFileDigest(digests map[string][sha256.Size]byte, src Source, raw []byte) {
+	fsrc, ok := findSource[*FileSource](src)
+	if !ok || fsrc.fsys != nil || fsrc.path == "" {
+		return
+	}
+	digests[fileDigestKey(fsrc.path)] = sha256.Sum256(raw)
+}
+
+// fileDigestKey 将路径规范化为绝对路径，保证 "a.yaml" 与 "./a.yaml" 指向同一条记录。
+func fileDigestKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
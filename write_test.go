@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lifei6671/go-config/decoder"
+)
+
+func TestDefaultConfig_WriteConfig(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "app.yaml")
+	require.NoError(t, os.WriteFile(base, []byte("server:\n  port: 8080\n"), 0o600))
+
+	cfg := newFileConfig()
+	require.NoError(t, cfg.Load(NewFileSource(base)))
+	cfg.Set("server.port", 9090)
+	cfg.Set("log.level", "debug")
+
+	t.Run("OverridesOnly", func(t *testing.T) {
+		out := filepath.Join(dir, "overrides.json")
+		require.NoError(t, cfg.WriteConfig(out, WithWriteOverridesOnly()))
+		b, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"server":{"port":9090},"log":{"level":"debug"}}`, string(b))
+	})
+
+	t.Run("SafeWriteLoadedFile", func(t *testing.T) {
+		require.NoError(t, cfg.SafeWriteConfig(base))
+		b, err := os.ReadFile(base)
+		require.NoError(t, err)
+		assert.Equal(t, "log:\n  level: debug\nserver:\n  port: 9090\n", string(b))
+
+		fi, err := os.Stat(base)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+		// 连续写入以上一次写入的内容为基准
+		require.NoError(t, cfg.SafeWriteConfig(base))
+	})
+
+	t.Run("SafeWriteRefusesExternalChange", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(base, []byte("server:\n  port: 1\n"), 0o600))
+		assert.ErrorIs(t, cfg.SafeWriteConfig(base), ErrConfigFileChanged)
+		assert.NoError(t, cfg.WriteConfig(base))
+	})
+
+	t.Run("SafeWriteRefusesUnknownFile", func(t *testing.T) {
+		other := filepath.Join(dir, "other.yaml")
+		require.NoError(t, os.WriteFile(other, []byte("a: 1\n"), 0o644))
+		assert.ErrorIs(t, cfg.SafeWriteConfig(other), ErrConfigFileExists)
+	})
+
+	t.Run("OverridesSurviveReload", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(base, []byte("server:\n  port: 8080\n"), 0o600))
+		require.NoError(t, cfg.Load(NewFileSource(base)))
+		port, _ := cfg.GetInt("server.port")
+		assert.Equal(t, 9090, port)
+		pos, _ := cfg.Position("server.port")
+		assert.Equal(t, overrideSourceName, pos.Source)
+	})
+}
+
+func TestDefaultConfig_WriteConfigKeepsRawValues(t *testing.T) {
+	key, err := GenerateAESGCMKey()
+	require.NoError(t, err)
+	d, err := parseAESGCMKeys([]string{"k1:" + key})
+	require.NoError(t, err)
+	password, err := EncryptValue(d, "p@ss")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "app.yaml")
+	content := "db:\n  host: ${env.DB_HOST}\n  password: " + password + "\n"
+	require.NoError(t, os.WriteFile(base, []byte(content), 0o600))
+	t.Setenv("DB_HOST", "10.0.0.1")
+
+	cfg := NewDefaultConfig(
+		WithDecoder(decoder.YAMLDecoder{}),
+		WithDecoder(decoder.JSONDecoder{}),
+		WithEncoder(decoder.YAMLEncoder{}),
+		WithVariableExpander(DefaultVariableExpander{}),
+		WithDecryptor(d),
+	)
+	cfg.EnableEnvExpand()
+	require.NoError(t, cfg.Load(NewFileSource(base)))
+	host, _ := cfg.GetString("db.host")
+	assert.Equal(t, "10.0.0.1", host)
+
+	t.Run("NoPlaintext", func(t *testing.T) {
+		out := filepath.Join(dir, "out.yaml")
+		require.NoError(t, cfg.WriteConfig(out))
+		b, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Equal(t, "db:\n  host: ${env.DB_HOST}\n  password: "+password+"\n", string(b))
+		assert.NotContains(t, string(b), "p@ss")
+
+		fi, err := os.Stat(out)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	})
+
+	t.Run("RefusesSensitiveSource", func(t *testing.T) {
+		secrets := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(secrets, "api__token"), []byte("s3cr3t\n"), 0o600))
+		require.NoError(t, cfg.Load(NewFileSource(base), NewSecretsDirSource(secrets)))
+
+		out := filepath.Join(dir, "leak.yaml")
+		err := cfg.WriteConfig(out)
+		assert.ErrorIs(t, err, ErrSensitiveWrite)
+		assert.Contains(t, err.Error(), "api.token")
+		assert.NotContains(t, err.Error(), "s3cr3t")
+		_, statErr := os.Stat(out)
+		assert.ErrorIs(t, statErr, os.ErrNotExist)
+
+		cfg.Set("log.level", "debug")
+		assert.NoError(t, cfg.WriteConfig(out, WithWriteOverridesOnly()))
+	})
+}